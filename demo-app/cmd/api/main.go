@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log"
 	"net/http"
 	"os"
@@ -10,120 +11,169 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/misua/eks-with-otel/demo-app/internal/config"
 	"github.com/misua/eks-with-otel/demo-app/internal/handlers"
 	"github.com/misua/eks-with-otel/demo-app/internal/middleware"
+	"github.com/misua/eks-with-otel/demo-app/internal/models"
+	"github.com/misua/eks-with-otel/demo-app/internal/server"
 	"github.com/misua/eks-with-otel/demo-app/internal/storage"
-	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
-)
-
-const (
-	serviceName    = "eks-otel-demo"
-	serviceVersion = "1.0.0"
+	"github.com/misua/eks-with-otel/demo-app/internal/telemetry"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 func main() {
-	// Get configuration from environment variables
-	port := getEnv("PORT", "8080")
-	otlpEndpoint := getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://otel-collector.tracing.svc.cluster.local:4318")
+	startedAt := time.Now()
 
-	// Initialize OpenTelemetry tracing
-	cleanup, err := middleware.InitTracer(serviceName, serviceVersion, otlpEndpoint)
+	// Resolve configuration once, everything below reads from cfg
+	cfg, err := config.Load()
 	if err != nil {
-		log.Fatalf("Failed to initialize OpenTelemetry: %v", err)
+		log.Fatalf("Invalid configuration: %v", err)
 	}
-	defer cleanup()
 
 	// Initialize structured logger
 	logger := middleware.InitLogger()
-	logger.WithField("service", serviceName).Info("Starting application")
+	logger.WithField("service", server.ServiceName).Info("Starting application")
+	// kill -USR1 cycles the log level info→debug→trace→info
+	middleware.CycleLogLevelOn(logger, syscall.SIGUSR1)
+
+	// Configure item IDs before telemetry so the strategy lands on the resource
+	models.SetIDStrategy(cfg.IDStrategy)
+	logger.WithField("id_strategy", models.IDStrategy()).Info("Item ID strategy configured")
+	middleware.AddResourceAttributes(attribute.String("id.strategy", models.IDStrategy()))
+
+	// Initialize OpenTelemetry tracing
+	tracing, err := middleware.InitTracer(server.ServiceName, server.ServiceVersion, cfg.OTLPEndpoint, middleware.TracerOptions{
+		Required:      cfg.OTELRequired,
+		RetryInterval: cfg.OTELRetryInterval,
+		Logger:        logger,
+		Propagators:   cfg.Propagators,
+		Batch: middleware.BatchOptions{
+			MaxQueueSize:       cfg.BSPMaxQueueSize,
+			MaxExportBatchSize: cfg.BSPMaxExportBatchSize,
+			ExportTimeout:      cfg.BSPExportTimeout,
+			ScheduleDelay:      cfg.BSPScheduleDelay,
+		},
+	})
+	if err != nil {
+		log.Fatalf("Failed to initialize OpenTelemetry: %v", err)
+	}
+	defer tracing.Shutdown()
+
+	// Initialize OpenTelemetry metrics
+	meterCleanup, err := middleware.InitMeter(server.ServiceName, server.ServiceVersion, cfg.OTLPEndpoint)
+	if err != nil {
+		log.Fatalf("Failed to initialize OpenTelemetry metrics: %v", err)
+	}
+	defer meterCleanup()
+
+	// Configure span attribute detail
+	telemetry.SetDetailLevel(cfg.SpanDetailLevel)
+	telemetry.SetRecordClientIP(cfg.SpanClientIP)
+	logger.WithFields(logrus.Fields{
+		"span_detail_level": telemetry.DetailLevel(),
+		"span_client_ip":    telemetry.RecordClientIP(),
+	}).Info("Span detail level configured")
+
+	// Configure item normalization
+	models.SetDescriptionPolicy(models.DescriptionPolicy{
+		Max:      cfg.DescriptionMax,
+		Truncate: cfg.DescriptionOverflow == "truncate",
+	})
+	models.SetMetadataKeys(cfg.MetadataKeys)
+	models.SetTimeFormat(cfg.TimeFormat)
 
 	// Initialize storage
-	memStorage := storage.NewMemoryStorage()
+	memStorage := storage.NewMemoryStorageWithOptions(storage.Options{
+		Shards:       cfg.StorageShards,
+		HistoryLimit: cfg.HistoryLimit,
+		MaxItems:     cfg.StorageMaxItems,
+		FullPolicy:   cfg.StorageFullPolicy,
+		ReadCacheTTL: cfg.ReadCacheTTL,
+	})
+	defer memStorage.Close()
 
 	// Initialize handlers
-	itemHandler := handlers.NewItemHandler(memStorage, logger)
+	handlers.ConfigureResponses(handlers.ResponseOptions{
+		Envelope: cfg.APIEnvelope,
+		Pretty:   cfg.PrettyJSON,
+	})
+	handlers.ConfigureTenants(cfg.TenantAdminIDs)
+	readiness := &handlers.Readiness{}
+	drain := middleware.NewDrain()
 
 	// Set Gin mode
 	gin.SetMode(gin.ReleaseMode)
+	defer middleware.RedirectGinOutput(logger)()
 
-	// Create Gin router
-	router := gin.New()
-
-	// Add middleware
-	router.Use(middleware.RecoveryMiddleware(logger))
-	router.Use(middleware.LoggingMiddleware(logger))
-	router.Use(otelgin.Middleware(serviceName)) // OpenTelemetry middleware
-
-	// Add CORS middleware for development
-	router.Use(func(c *gin.Context) {
-		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization")
-		
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
-			return
-		}
-		
-		c.Next()
-	})
-
-	// Health check endpoint
-	router.GET("/health", itemHandler.HealthCheck)
-	router.GET("/", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{
-			"service": serviceName,
-			"version": serviceVersion,
-			"status":  "running",
-		})
+	router := server.NewRouter(memStorage, logger, server.RouterOptions{
+		Config:    cfg,
+		Readiness: readiness,
+		Drain:     drain,
+		Tracing:   tracing,
 	})
 
-	// API routes
-	v1 := router.Group("/api/v1")
-	{
-		v1.GET("/items", itemHandler.GetItems)
-		v1.GET("/items/:id", itemHandler.GetItem)
-		v1.POST("/items", itemHandler.CreateItem)
-		v1.PUT("/items/:id", itemHandler.UpdateItem)
-		v1.DELETE("/items/:id", itemHandler.DeleteItem)
-	}
-
 	// Create HTTP server
-	server := &http.Server{
-		Addr:    ":" + port,
-		Handler: router,
+	httpServer := &http.Server{
+		Addr:        ":" + cfg.Port,
+		Handler:     router,
+		BaseContext: drain.BaseContext,
+		// net/http reports connection errors through its own logger
+		ErrorLog: log.New(logger.WithField("source", "http").WriterLevel(logrus.WarnLevel), "", 0),
 	}
 
 	// Start server in a goroutine
 	go func() {
-		logger.WithField("port", port).Info("Starting HTTP server")
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		logger.WithField("port", cfg.Port).Info("Starting HTTP server")
+		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			logger.WithError(err).Fatal("Failed to start HTTP server")
 		}
 	}()
 
+	// Periodic snapshots carry the store across restarts, starting from the
+	// last one written. The restore runs once the server listens, so /readyz
+	// answers 503 meanwhile instead of connections being refused.
+	if cfg.SnapshotInterval > 0 {
+		restored, err := memStorage.LoadSnapshot(context.Background(), cfg.SnapshotPath)
+		switch {
+		case err == nil:
+			logger.WithFields(logrus.Fields{"path": cfg.SnapshotPath, "items": restored}).Info("Restored storage snapshot")
+		case errors.Is(err, os.ErrNotExist):
+			logger.WithField("path", cfg.SnapshotPath).Info("No storage snapshot to restore")
+		default:
+			logger.WithError(err).WithField("path", cfg.SnapshotPath).Warn("Ignoring storage snapshot that cannot be restored")
+		}
+		memStorage.StartSnapshots(cfg.SnapshotPath, cfg.SnapshotInterval)
+		logger.WithField("interval", cfg.SnapshotInterval.String()).Info("Periodic storage snapshots enabled")
+	}
+
+	// Only report ready once storage answers; a real backend would also run
+	// its migrations here
+	pingCtx, pingCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	if err := memStorage.Ping(pingCtx); err != nil {
+		logger.WithError(err).Fatal("Storage not available")
+	}
+	pingCancel()
+	readiness.MarkReady()
+	logger.WithField("startup_ms", time.Since(startedAt).Milliseconds()).Info("Service ready")
+
 	// Wait for interrupt signal for graceful shutdown
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
-	logger.Info("Shutting down server...")
+	logger.WithField("drain_timeout", cfg.ShutdownTimeout.String()).Info("Shutting down server...")
+	drain.Start()
 
 	// Graceful shutdown with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
 	defer cancel()
 
-	if err := server.Shutdown(ctx); err != nil {
-		logger.WithError(err).Error("Server forced to shutdown")
+	if err := httpServer.Shutdown(ctx); err != nil {
+		// Cancel what is left so those requests answer 503 and end their spans
+		aborted := drain.Abort(2 * time.Second)
+		logger.WithError(err).WithField("aborted_requests", aborted).Error("Server forced to shutdown")
 	} else {
 		logger.Info("Server shutdown completed")
 	}
 }
-
-// getEnv gets environment variable with fallback
-func getEnv(key, fallback string) string {
-	if value, exists := os.LookupEnv(key); exists {
-		return value
-	}
-	return fallback
-}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+var errCircuitOpen = errors.New("circuit breaker open")
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// circuitBreaker stops sending requests to a target after a run of consecutive
+// failures, waits for a cooldown and then lets a single probe through before
+// resuming normal traffic
+type circuitBreaker struct {
+	target    string
+	threshold int
+	cooldown  time.Duration
+
+	mu        sync.Mutex
+	state     breakerState
+	failures  int
+	openedAt  time.Time
+	openTotal time.Duration
+	trips     int
+}
+
+func newCircuitBreaker(target string, threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		target:    target,
+		threshold: threshold,
+		cooldown:  cooldown,
+	}
+}
+
+// Blocked reports whether callers should skip the target without sending anything
+func (b *circuitBreaker) Blocked() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		return time.Since(b.openedAt) < b.cooldown
+	case breakerHalfOpen:
+		return true
+	}
+	return false
+}
+
+// Allow reports whether a request may be sent. Once the cooldown has elapsed
+// the first caller is let through as the probe and the rest keep waiting.
+func (b *circuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if time.Since(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = breakerHalfOpen
+		fmt.Printf("🔁 Circuit half-open for %s, sending probe request\n", b.target)
+		return true
+	case breakerHalfOpen:
+		return false
+	}
+	return true
+}
+
+// Record feeds the outcome of a request into the breaker
+func (b *circuitBreaker) Record(success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if success {
+		if b.state != breakerClosed {
+			b.openTotal += time.Since(b.openedAt)
+			fmt.Printf("✅ Circuit closed for %s, resuming traffic\n", b.target)
+		}
+		b.state = breakerClosed
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	switch {
+	case b.state == breakerHalfOpen:
+		// Probe failed, stay open for another cooldown
+		b.openTotal += time.Since(b.openedAt)
+		b.state = breakerOpen
+		b.openedAt = time.Now()
+		fmt.Printf("🔌 Probe to %s failed, circuit stays open for %v\n", b.target, b.cooldown)
+	case b.state == breakerClosed && b.failures >= b.threshold:
+		b.state = breakerOpen
+		b.openedAt = time.Now()
+		b.trips++
+		fmt.Printf("🔌 Circuit opened for %s after %d consecutive failures, pausing for %v\n",
+			b.target, b.failures, b.cooldown)
+	}
+}
+
+// OpenTime returns the total time spent with the circuit open or half-open
+func (b *circuitBreaker) OpenTime() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	total := b.openTotal
+	if b.state != breakerClosed {
+		total += time.Since(b.openedAt)
+	}
+	return total
+}
+
+// Trips returns how many times the circuit opened
+func (b *circuitBreaker) Trips() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.trips
+}
+
+// breakerTransport routes every request through the circuit breaker of its
+// target host. Transport errors and 5xx responses count as failures.
+type breakerTransport struct {
+	next      http.RoundTripper
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	breakers map[string]*circuitBreaker
+}
+
+func newBreakerTransport(next http.RoundTripper, threshold int, cooldown time.Duration) *breakerTransport {
+	return &breakerTransport{
+		next:      next,
+		threshold: threshold,
+		cooldown:  cooldown,
+		breakers:  make(map[string]*circuitBreaker),
+	}
+}
+
+// For returns the breaker for a target host, creating it on first use
+func (t *breakerTransport) For(host string) *circuitBreaker {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	b, ok := t.breakers[host]
+	if !ok {
+		b = newCircuitBreaker(host, t.threshold, t.cooldown)
+		t.breakers[host] = b
+	}
+	return b
+}
+
+// Breakers returns a snapshot of all breakers created so far
+func (t *breakerTransport) Breakers() []*circuitBreaker {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	breakers := make([]*circuitBreaker, 0, len(t.breakers))
+	for _, b := range t.breakers {
+		breakers = append(breakers, b)
+	}
+	return breakers
+}
+
+func (t *breakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	b := t.For(req.URL.Host)
+	if !b.Allow() {
+		return nil, errCircuitOpen
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	b.Record(err == nil && resp.StatusCode < 500)
+	return resp, err
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// requestBudget caps the number of requests across all workers. Each request
+// takes from it right before it is sent. A nil budget is unlimited.
+type requestBudget struct {
+	max  int64
+	used atomic.Int64
+
+	once sync.Once
+	done chan struct{}
+}
+
+// newRequestBudget returns nil when max is not positive so runs stay time bound
+func newRequestBudget(max int) *requestBudget {
+	if max <= 0 {
+		return nil
+	}
+	return &requestBudget{max: int64(max), done: make(chan struct{})}
+}
+
+// Take reserves one request and reports whether the budget allowed it. The
+// reservation that exhausts the budget closes Done.
+func (b *requestBudget) Take() bool {
+	if b == nil {
+		return true
+	}
+	n := b.used.Add(1)
+	if n >= b.max {
+		b.once.Do(func() { close(b.done) })
+	}
+	return n <= b.max
+}
+
+// Exhausted reports whether every request of the budget was handed out
+func (b *requestBudget) Exhausted() bool {
+	return b != nil && b.used.Load() >= b.max
+}
+
+// Done is closed once every request of the budget was handed out
+func (b *requestBudget) Done() <-chan struct{} {
+	if b == nil {
+		return nil
+	}
+	return b.done
+}
+
+// Used returns how many requests were handed out, capped at the budget
+func (b *requestBudget) Used() int64 {
+	return min(b.used.Load(), b.max)
+}
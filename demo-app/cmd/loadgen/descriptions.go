@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+var loremWords = strings.Fields(`lorem ipsum dolor sit amet consectetur adipiscing elit sed do
+	eiusmod tempor incididunt ut labore et dolore magna aliqua ut enim ad minim veniam quis
+	nostrud exercitation ullamco laboris nisi ut aliquip ex ea commodo consequat`)
+
+// descriptionGenerator sizes item descriptions. With no size range set the
+// short "Generated by load test at ..." texts are sent unchanged; otherwise
+// they are padded with lorem text to a length drawn uniformly from min to max
+// characters, so some payloads are large.
+type descriptionGenerator struct {
+	min, max int
+}
+
+// newDescriptionGenerator reads the size range; a max below min is raised to min
+func newDescriptionGenerator(min, max int) descriptionGenerator {
+	if max > 0 && max < min {
+		fmt.Printf("⚠️  DESC_SIZE_MAX=%d is below DESC_SIZE_MIN=%d, using %d\n", max, min, min)
+		max = min
+	}
+	if max == 0 {
+		max = min
+	}
+	return descriptionGenerator{min: min, max: max}
+}
+
+// Next returns base sized to the configured range
+func (g descriptionGenerator) Next(base string) string {
+	if g.max == 0 {
+		return base
+	}
+	size := g.min + rng.Intn(g.max-g.min+1)
+
+	var b strings.Builder
+	b.Grow(size + 16)
+	b.WriteString(base)
+	for b.Len() < size {
+		b.WriteByte(' ')
+		b.WriteString(loremWords[rng.Intn(len(loremWords))])
+	}
+	return b.String()[:size]
+}
+
+func (g descriptionGenerator) String() string {
+	if g.max == 0 {
+		return "short"
+	}
+	return fmt.Sprintf("%d to %d characters", g.min, g.max)
+}
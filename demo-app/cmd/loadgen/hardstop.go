@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// defaultHardStopGrace is how long past LOAD_DURATION a run may take before
+// it is force-quit
+const defaultHardStopGrace = 30 * time.Second
+
+// exitHardStop is the exit code of a run that had to be force-quit, apart
+// from the 1 of failed startup checks
+const exitHardStop = 2
+
+// hardStop is the safety net around the graceful shutdown: should a hung
+// worker or request keep the run going past its deadline, the process exits
+// with exitHardStop instead of leaving a CI job waiting forever
+type hardStop struct {
+	deadline time.Duration
+	timer    *time.Timer
+}
+
+// armHardStop starts the timer for deadline after now. A zero deadline
+// leaves the run without one.
+func (lg *LoadGenerator) armHardStop(deadline time.Duration) *hardStop {
+	if deadline <= 0 {
+		return &hardStop{}
+	}
+	return &hardStop{
+		deadline: deadline,
+		timer: time.AfterFunc(deadline, func() {
+			fmt.Printf("\n💀 Hard stop: run still going %v after it started, force-quitting with %d requests in flight (exit code %d)\n",
+				deadline, lg.inFlight.inFlight.Load(), exitHardStop)
+			os.Exit(exitHardStop)
+		}),
+	}
+}
+
+// disarm stops the timer once the graceful path is done
+func (h *hardStop) disarm() {
+	if h.timer != nil {
+		h.timer.Stop()
+	}
+}
+
+func (h *hardStop) String() string {
+	if h.deadline <= 0 {
+		return "off"
+	}
+	return fmt.Sprintf("after %v, not fired", h.deadline)
+}
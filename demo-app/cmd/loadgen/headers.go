@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// secretHeaderHints mark header names whose values are hidden in the banner
+var secretHeaderHints = []string{"auth", "key", "token", "secret", "password", "cookie"}
+
+// parseHeaders reads LOAD_HEADERS in the form "Name:value,Name2:value2",
+// warning about and skipping entries without a name
+func parseHeaders(spec string) http.Header {
+	headers := http.Header{}
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(entry, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			fmt.Printf("⚠️  Ignoring LOAD_HEADERS entry %q, expected Name:value\n", entry)
+			continue
+		}
+		headers.Add(name, strings.TrimSpace(value))
+	}
+	return headers
+}
+
+// describeHeaders lists the headers for the startup banner with secret looking
+// values redacted
+func describeHeaders(headers http.Header) string {
+	if len(headers) == 0 {
+		return "none"
+	}
+	parts := make([]string, 0, len(headers))
+	for name, values := range headers {
+		value := strings.Join(values, ",")
+		lower := strings.ToLower(name)
+		for _, hint := range secretHeaderHints {
+			if strings.Contains(lower, hint) {
+				value = "REDACTED"
+				break
+			}
+		}
+		parts = append(parts, name+": "+value)
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ", ")
+}
+
+// headerTransport sets the configured headers on every outgoing request
+type headerTransport struct {
+	next    http.RoundTripper
+	headers http.Header
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if len(t.headers) == 0 {
+		return t.next.RoundTrip(req)
+	}
+	// RoundTrippers must not modify the caller's request
+	req = req.Clone(req.Context())
+	for name, values := range t.headers {
+		req.Header[name] = values
+	}
+	return t.next.RoundTrip(req)
+}
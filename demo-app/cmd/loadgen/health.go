@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Load generator modes
+const (
+	modeMixed    = "mixed"
+	modeHealth   = "health"
+	modeScenario = "scenario"
+)
+
+// loadMode reads MODE, falling back to the mixed CRUD traffic
+func loadMode() string {
+	mode := getEnv("MODE", modeMixed)
+	switch mode {
+	case modeMixed, modeHealth, modeScenario:
+		return mode
+	default:
+		fmt.Printf("⚠️  Unknown MODE %q, using %s\n", mode, modeMixed)
+		return modeMixed
+	}
+}
+
+// latencyRecorder keeps every observed latency for percentiles at the end
+type latencyRecorder struct {
+	mu      sync.Mutex
+	samples []time.Duration
+}
+
+func (r *latencyRecorder) Record(d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.samples = append(r.samples, d)
+}
+
+// Percentiles returns the nearest-rank value for each of ps, nil without samples
+func (r *latencyRecorder) Percentiles(ps ...float64) []time.Duration {
+	r.mu.Lock()
+	sorted := slices.Clone(r.samples)
+	r.mu.Unlock()
+	if len(sorted) == 0 {
+		return nil
+	}
+	slices.Sort(sorted)
+
+	out := make([]time.Duration, len(ps))
+	for i, p := range ps {
+		rank := int(p/100*float64(len(sorted))+0.5) - 1
+		out[i] = sorted[min(max(rank, 0), len(sorted)-1)]
+	}
+	return out
+}
+
+// printHealthLatency reports /health latency percentiles
+func (lg *LoadGenerator) printHealthLatency() {
+	p := lg.healthLatency.Percentiles(50, 90, 99, 100)
+	if p == nil {
+		return
+	}
+	fmt.Printf("\nHealth Check Latency:\n")
+	fmt.Printf("  p50: %v, p90: %v, p99: %v, max: %v\n",
+		p[0].Round(time.Microsecond), p[1].Round(time.Microsecond), p[2].Round(time.Microsecond), p[3].Round(time.Microsecond))
+}
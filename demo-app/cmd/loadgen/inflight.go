@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// defaultStopGrace is how long requests still in flight at shutdown may
+// finish before they are cancelled
+const defaultStopGrace = 2 * time.Second
+
+var errStopped = errors.New("load generator stopped")
+
+// inFlightTransport tracks requests in flight so shutdown can tell apart
+// requests that completed from those the generator abandoned. Every request
+// runs under a context that Stop cancels once the grace period is over.
+type inFlightTransport struct {
+	next http.RoundTripper
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	wg       sync.WaitGroup
+	inFlight atomic.Int64
+	stopping atomic.Bool
+
+	// Outcomes of the requests that were in flight when Stop was called
+	completed atomic.Int64
+	cancelled atomic.Int64
+}
+
+func newInFlightTransport(next http.RoundTripper) *inFlightTransport {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &inFlightTransport{next: next, ctx: ctx, cancel: cancel}
+}
+
+func (t *inFlightTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// No new requests once shutdown started
+	if t.stopping.Load() {
+		return nil, errStopped
+	}
+
+	t.wg.Add(1)
+	t.inFlight.Add(1)
+	defer func() {
+		t.inFlight.Add(-1)
+		t.wg.Done()
+	}()
+
+	// Keep the request's own deadline (the client timeout) and add ours
+	ctx, cancel := context.WithCancel(req.Context())
+	defer cancel()
+	stop := context.AfterFunc(t.ctx, cancel)
+	defer stop()
+
+	resp, err := t.next.RoundTrip(req.WithContext(ctx))
+	if t.stopping.Load() {
+		if err != nil && t.ctx.Err() != nil {
+			t.cancelled.Add(1)
+		} else {
+			t.completed.Add(1)
+		}
+	}
+	return resp, err
+}
+
+// Stopping reports whether Stop has been called
+func (t *inFlightTransport) Stopping() bool {
+	return t.stopping.Load()
+}
+
+// Stop refuses new requests, gives the ones in flight grace to finish and
+// cancels the rest. It returns how many were in flight when it was called.
+func (t *inFlightTransport) Stop(grace time.Duration) int64 {
+	t.stopping.Store(true)
+	inFlight := t.inFlight.Load()
+
+	if !t.wait(grace) {
+		t.cancel()
+		t.wait(grace)
+	}
+	return inFlight
+}
+
+// wait waits for the requests in flight, up to timeout
+func (t *inFlightTransport) wait(timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		t.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import "sync"
+
+// itemPool holds the IDs of items the generator knows to exist. Every worker
+// adds, picks and removes IDs, so all access goes through its mutex.
+type itemPool struct {
+	mu  sync.Mutex
+	ids []string
+}
+
+func (p *itemPool) Add(id string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.ids = append(p.ids, id)
+}
+
+// Replace swaps the pool contents for the IDs a listing returned
+func (p *itemPool) Replace(ids []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.ids = ids
+}
+
+func (p *itemPool) Remove(id string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i, known := range p.ids {
+		if known == id {
+			p.ids = append(p.ids[:i], p.ids[i+1:]...)
+			return
+		}
+	}
+}
+
+// Pick returns a random known ID, false when the pool is empty
+func (p *itemPool) Pick() (string, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.ids) == 0 {
+		return "", false
+	}
+	return p.ids[rng.Intn(len(p.ids))], true
+}
+
+func (p *itemPool) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.ids)
+}
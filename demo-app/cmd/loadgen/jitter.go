@@ -0,0 +1,26 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// defaultStartJitter bounds the random delay before each worker's first request
+const defaultStartJitter = 500 * time.Millisecond
+
+// startJitter draws a worker's initial delay, uniform in [0, max), so workers
+// started together do not fire their first requests, and with similar think
+// times the ones after, in lockstep
+func startJitter(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+	return time.Duration(rng.Int63n(int64(max)))
+}
+
+// debugf prints detail only wanted with LOG_LEVEL=debug
+func (lg *LoadGenerator) debugf(format string, args ...any) {
+	if lg.debug {
+		fmt.Printf("🐛 "+format+"\n", args...)
+	}
+}
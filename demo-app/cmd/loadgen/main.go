@@ -6,10 +6,11 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"math/rand"
 	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
+	"sync/atomic"
 	"syscall"
 	"time"
 )
@@ -18,14 +19,40 @@ const (
 	defaultBaseURL = "http://localhost:8080"
 	defaultDuration = 5 * time.Minute
 	defaultConcurrency = 3
+	defaultStartupRetries = 30
+	defaultStartupInterval = 2 * time.Second
+	defaultBreakerThreshold = 5
+	defaultBreakerCooldown = 10 * time.Second
+	defaultDrainGrace = 10 * time.Second
+	defaultThinkTimeMin = 100 * time.Millisecond
+	defaultThinkTimeMax = 2 * time.Second
+	defaultThinkTimeMean = time.Second
 )
 
+// Operation categories; the mixed category draws from both reads and writes
+const (
+	categoryMixed = "mixed"
+	categoryRead = "read"
+	categoryWrite = "write"
+)
+
+// workerPool is a group of workers generating one category of operations
+type workerPool struct {
+	category string
+	size     int
+}
+
+// Item is the part of the API's item the generator reads and writes. The
+// timestamps are left out: the server writes them as RFC3339 strings or epoch
+// milliseconds depending on TIME_FORMAT, and nothing here uses them.
 type Item struct {
-	ID          string    `json:"id,omitempty"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	CreatedAt   time.Time `json:"created_at,omitempty"`
-	UpdatedAt   time.Time `json:"updated_at,omitempty"`
+	ID          string `json:"id,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Version is read from responses only, the server ignores it on writes
+	Version int `json:"version,omitempty"`
+	// Metadata carries the search tag with SEARCH_ENABLED
+	Metadata map[string]any `json:"metadata,omitempty"`
 }
 
 type ItemsResponse struct {
@@ -36,41 +63,223 @@ type ItemsResponse struct {
 type LoadGenerator struct {
 	baseURL    string
 	client     *http.Client
-	itemIDs    []string
+	itemIDs    itemPool
 	stats      *Stats
+
+	startupRetries  int
+	startupInterval time.Duration
+
+	target   string
+	breakers *breakerTransport
+	inFlight *inFlightTransport
+	// statuses counts load traffic responses by status code
+	statuses statusCounts
+
+	// draining is set on the first interrupt: writes stop, reads continue
+	draining atomic.Bool
+
+	thinkTime    thinkTime
+	// startJitter bounds the random delay before each worker's first request
+	startJitter  time.Duration
+	names        *nameGenerator
+	descriptions descriptionGenerator
+
+	// verify reads every created or updated item back and checks its fields
+	verify bool
+	// debug prints per-worker detail, set with LOG_LEVEL=debug
+	debug bool
+	// patchEnabled turns half of the updates into PATCH partial updates,
+	// off by default for servers without PATCH /api/v1/items/:id
+	patchEnabled bool
+	// searchEnabled tags created items with their name in metadata and
+	// turns a third of the listings into searches on that tag
+	searchEnabled bool
+	searchTerms   searchTerms
+
+	// budget stops the run after MAX_REQUESTS requests instead of after
+	// the duration, nil for time bound runs
+	budget  *requestBudget
+	endedBy string
+
+	// hardStop force-quits runs that overrun their deadline
+	hardStop *hardStop
+
+	// mode is mixed CRUD traffic or health checks only
+	mode          string
+	// scenarioKey is the baggage member carrying the scenario ID
+	scenarioKey   string
+	healthLatency latencyRecorder
+
+	// createsPaused is set while the store is above MAX_STORE_ITEMS; creates
+	// turn into deletes, or listings when there is nothing to delete
+	createsPaused atomic.Bool
+
+	startedAt time.Time
+	endedAt   time.Time
 }
 
+// Stats counts requests by outcome and operation. Every worker records into
+// it, so the counters are atomic.
 type Stats struct {
-	TotalRequests   int
-	SuccessRequests int
-	FailedRequests  int
-	CreateCount     int
-	ReadCount       int
-	UpdateCount     int
-	DeleteCount     int
-	HealthCount     int
+	TotalRequests   atomic.Int64
+	SuccessRequests atomic.Int64
+	FailedRequests  atomic.Int64
+	CreateCount     atomic.Int64
+	ReadCount       atomic.Int64
+	UpdateCount     atomic.Int64
+	PatchCount      atomic.Int64
+	SearchCount     atomic.Int64
+	SearchHits      atomic.Int64
+	SearchMisses    atomic.Int64
+	DeleteCount     atomic.Int64
+	HealthCount     atomic.Int64
+
+	// ConsistencyErrors counts VERIFY reads that did not match what was written
+	ConsistencyErrors atomic.Int64
+	// VerifySkipped counts VERIFY reads of items another worker had already
+	// written again, which cannot be compared
+	VerifySkipped atomic.Int64
+
+	// ScenarioCount and ScenarioFailures count MODE=scenario iterations
+	ScenarioCount    atomic.Int64
+	ScenarioFailures atomic.Int64
+
+	// CreatePauses counts how often MAX_STORE_ITEMS paused creates
+	CreatePauses atomic.Int64
+
+	// InFlightAtStop counts requests still running when load generation ended
+	InFlightAtStop atomic.Int64
 }
 
 func main() {
 	baseURL := getEnv("DEMO_APP_URL", defaultBaseURL)
-	duration := parseDuration(getEnv("LOAD_DURATION", "5m"))
-	concurrency := parseInt(getEnv("CONCURRENCY", "3"))
+	seed := loadSeed()
+	duration := loadDuration()
+	maxRequests := envCount("MAX_REQUESTS", 0)
+	mode := loadMode()
+	scenarioKey := scenarioBaggageKey()
+	maxStoreItems := envCount("MAX_STORE_ITEMS", 0)
+	storeCheckInterval := parseDuration(getEnv("STORE_CHECK_INTERVAL", ""), defaultStoreCheckInterval)
+	tracingStatus, shutdownTracing := initTracing()
+	defer shutdownTracing()
+	maxConcurrency := envCount("MAX_CONCURRENCY", defaultMaxConcurrency)
+	concurrency := clampConcurrency("CONCURRENCY", envCount("CONCURRENCY", defaultConcurrency), maxConcurrency)
+	readConcurrency := clampConcurrency("CONCURRENCY_READ", envCount("CONCURRENCY_READ", 0), maxConcurrency)
+	writeConcurrency := clampConcurrency("CONCURRENCY_WRITE", envCount("CONCURRENCY_WRITE", 0), maxConcurrency)
+	startupRetries := parseInt(getEnv("STARTUP_RETRIES", ""), defaultStartupRetries)
+	startupInterval := parseDuration(getEnv("STARTUP_INTERVAL", ""), defaultStartupInterval)
+	breakerThreshold := parseInt(getEnv("BREAKER_THRESHOLD", ""), defaultBreakerThreshold)
+	breakerCooldown := parseDuration(getEnv("BREAKER_COOLDOWN", ""), defaultBreakerCooldown)
+	drainGrace := parseDuration(getEnv("DRAIN_GRACE", ""), defaultDrainGrace)
+	hardStopGrace := parseDuration(getEnv("HARD_STOP_GRACE", ""), defaultHardStopGrace)
+	think := newThinkTime(
+		getEnv("THINK_TIME_DIST", thinkTimeUniform),
+		parseDuration(getEnv("THINK_TIME_MIN", ""), defaultThinkTimeMin),
+		parseDuration(getEnv("THINK_TIME_MAX", ""), defaultThinkTimeMax),
+		parseDuration(getEnv("THINK_TIME_MEAN", ""), defaultThinkTimeMean),
+	)
+	startJitterMax := parseDuration(getEnv("START_JITTER", ""), defaultStartJitter)
+	verify := getEnv("VERIFY", "") == "true"
+	debug := getEnv("LOG_LEVEL", "") == "debug"
+	patchEnabled := getEnv("PATCH_ENABLED", "") == "true"
+	searchEnabled := getEnv("SEARCH_ENABLED", "") == "true"
+	headers := parseHeaders(getEnv("LOAD_HEADERS", ""))
+	progressInterval := parseDuration(getEnv("PROGRESS_INTERVAL", ""), defaultProgressInterval)
+	names := newNameGenerator(getEnv("NAME_DISTRIBUTION", nameRandom), envCount("NAME_VOCAB_SIZE", defaultNameVocabSize))
+	descriptions := newDescriptionGenerator(envCount("DESC_SIZE_MIN", 0), envCount("DESC_SIZE_MAX", 0))
+
+	// Per-category concurrency replaces the single mixed pool
+	pools := []workerPool{{category: categoryMixed, size: concurrency}}
+	if readConcurrency > 0 || writeConcurrency > 0 {
+		pools = []workerPool{
+			{category: categoryRead, size: readConcurrency},
+			{category: categoryWrite, size: writeConcurrency},
+		}
+	}
+
+	target := baseURL
+	if u, err := url.Parse(baseURL); err == nil && u.Host != "" {
+		target = u.Host
+	}
 
 	fmt.Printf("🚀 Starting Load Generator for EKS OpenTelemetry Demo\n")
 	fmt.Printf("====================================================\n")
 	fmt.Printf("Target URL: %s\n", baseURL)
-	fmt.Printf("Duration: %v\n", duration)
-	fmt.Printf("Concurrency: %d\n", concurrency)
+	if maxRequests > 0 {
+		fmt.Printf("Max Requests: %d (duration ignored)\n", maxRequests)
+	} else {
+		fmt.Printf("Duration: %v\n", duration)
+	}
+	fmt.Printf("Mode: %s\n", mode)
+	if mode == modeScenario {
+		fmt.Printf("Scenario Baggage: %s\n", scenarioKey)
+	}
+	fmt.Printf("Seed: %d (set SEED=%d to replay)\n", seed, seed)
+	if pools[0].category == categoryMixed {
+		fmt.Printf("Concurrency: %d\n", concurrency)
+	} else {
+		fmt.Printf("Concurrency: %d read, %d write\n", readConcurrency, writeConcurrency)
+	}
+	fmt.Printf("Startup Check: %d retries every %v\n", startupRetries, startupInterval)
+	fmt.Printf("Circuit Breaker: open after %d failures, cooldown %v\n", breakerThreshold, breakerCooldown)
+	fmt.Printf("Drain Grace: %v\n", drainGrace)
+	// Runs bounded by MAX_REQUESTS have no deadline to guard
+	var hardStopAfter time.Duration
+	if maxRequests == 0 && hardStopGrace > 0 {
+		hardStopAfter = duration + hardStopGrace
+		fmt.Printf("Hard Stop: force-quit %v after the start\n", hardStopAfter)
+	}
+	fmt.Printf("Think Time: %s\n", think)
+	fmt.Printf("Start Jitter: up to %v per worker\n", startJitterMax)
+	fmt.Printf("Item Names: %s\n", names)
+	fmt.Printf("Descriptions: %s\n", descriptions)
+	fmt.Printf("Headers: %s\n", describeHeaders(headers))
+	fmt.Printf("Tracing: %s\n", tracingStatus)
+	if maxStoreItems > 0 {
+		fmt.Printf("Max Store Items: %d (checked every %v)\n", maxStoreItems, storeCheckInterval)
+	}
+	if verify {
+		fmt.Printf("Verify: reading back every create and update\n")
+	}
+	if patchEnabled {
+		fmt.Printf("Patch: half of the updates sent as PATCH\n")
+	}
+	if searchEnabled {
+		fmt.Printf("Search: a third of the listings sent as searches\n")
+	}
 	fmt.Printf("====================================================\n\n")
 
+	// Custom headers go on every request, the startup check included
+	transport := &headerTransport{next: http.DefaultTransport, headers: headers}
+
 	// Create load generator
 	lg := &LoadGenerator{
 		baseURL: baseURL,
 		client: &http.Client{
-			Timeout: 10 * time.Second,
+			Timeout:   10 * time.Second,
+			Transport: transport,
 		},
-		itemIDs: make([]string, 0),
 		stats:   &Stats{},
+
+		startupRetries:  startupRetries,
+		startupInterval: startupInterval,
+
+		target:   target,
+		breakers: newBreakerTransport(transport, breakerThreshold, breakerCooldown),
+
+		thinkTime:    think,
+		startJitter:  startJitterMax,
+		names:        names,
+		descriptions: descriptions,
+		verify:       verify,
+		debug:        debug,
+
+		patchEnabled:  patchEnabled,
+		searchEnabled: searchEnabled,
+
+		budget:      newRequestBudget(maxRequests),
+		mode:        mode,
+		scenarioKey: scenarioKey,
 	}
 
 	// Wait for app to be ready
@@ -78,66 +287,133 @@ func main() {
 		log.Fatal("❌ Demo app is not responding. Make sure it's running.")
 	}
 
+	// Only guard load traffic with the circuit breaker, the startup check has its own retries
+	lg.inFlight = newInFlightTransport(lg.breakers)
+	lg.client.Transport = &statusTransport{next: lg.inFlight, statuses: &lg.statuses}
+
 	// Setup graceful shutdown
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 
 	// Start load generation
 	done := make(chan bool)
-	go lg.generateLoad(duration, concurrency, done)
+	lg.startedAt = time.Now()
+	lg.hardStop = lg.armHardStop(hardStopAfter)
+	go lg.generateLoad(duration, pools, done)
 
 	// Start stats reporting
 	go lg.reportStats()
+	go lg.reportProgress(duration, progressInterval)
+	if maxStoreItems > 0 {
+		go lg.watchStoreSize(maxStoreItems, storeCheckInterval)
+	}
 
 	// Wait for completion or interrupt
 	select {
 	case <-done:
 		fmt.Println("\n✅ Load generation completed successfully!")
 	case <-quit:
-		fmt.Println("\n🛑 Load generation interrupted by user")
+		// First interrupt: stop writing but keep reads flowing for a clean tail
+		lg.draining.Store(true)
+		fmt.Printf("\n🚰 Draining: stopped creates/updates/deletes, finishing reads for %v (Ctrl+C again to force exit)\n", drainGrace)
+
+		select {
+		case <-done:
+			fmt.Println("\n✅ Load generation completed while draining")
+		case <-time.After(drainGrace):
+			fmt.Println("\n🛑 Drain period over, load generation interrupted by user")
+		case <-quit:
+			fmt.Println("\n🛑 Load generation force-stopped by user")
+		}
 	}
 
+	// Let requests still in flight finish briefly, then cancel them. The grace
+	// period is left out of the throughput figures.
+	lg.endedAt = time.Now()
+	lg.stats.InFlightAtStop.Store(lg.inFlight.Stop(defaultStopGrace))
+	lg.hardStop.disarm()
+
 	lg.printFinalStats()
 }
 
 func (lg *LoadGenerator) waitForApp() bool {
 	fmt.Print("⏳ Waiting for demo app to be ready...")
-	for i := 0; i < 30; i++ {
+	start := time.Now()
+	lastStatus := "no response"
+	for i := 0; i < lg.startupRetries; i++ {
 		resp, err := lg.client.Get(lg.baseURL + "/health")
 		if err == nil && resp.StatusCode == 200 {
 			resp.Body.Close()
-			fmt.Println(" ✅ Ready!")
+			fmt.Printf(" ✅ Ready! (waited %v, %d attempts)\n", time.Since(start).Round(time.Millisecond), i+1)
 			return true
 		}
+		if err != nil {
+			lastStatus = err.Error()
+		}
 		if resp != nil {
+			lastStatus = resp.Status
 			resp.Body.Close()
 		}
 		fmt.Print(".")
-		time.Sleep(2 * time.Second)
+		time.Sleep(lg.startupInterval)
 	}
-	fmt.Println(" ❌ Failed!")
+	fmt.Printf(" ❌ Failed! (waited %v, %d attempts, last status: %s)\n",
+		time.Since(start).Round(time.Millisecond), lg.startupRetries, lastStatus)
 	return false
 }
 
-func (lg *LoadGenerator) generateLoad(duration time.Duration, concurrency int, done chan bool) {
+func (lg *LoadGenerator) generateLoad(duration time.Duration, pools []workerPool, done chan bool) {
 	endTime := time.Now().Add(duration)
 	
-	// Start worker goroutines
-	for i := 0; i < concurrency; i++ {
-		go lg.worker(i, endTime)
+	// Start worker goroutines, numbered across all pools
+	workerID := 0
+	for _, pool := range pools {
+		for i := 0; i < pool.size; i++ {
+			go lg.worker(workerID, pool.category, endTime)
+			workerID++
+		}
 	}
 
-	// Wait for duration
-	time.Sleep(duration)
+	// Wait for duration, or until MAX_REQUESTS requests were sent
+	if lg.budget != nil {
+		<-lg.budget.Done()
+		lg.endedBy = "request count"
+	} else {
+		time.Sleep(duration)
+		lg.endedBy = "time"
+	}
 	done <- true
 }
 
-func (lg *LoadGenerator) worker(workerID int, endTime time.Time) {
-	fmt.Printf("🔧 Worker %d started\n", workerID)
+func (lg *LoadGenerator) worker(workerID int, category string, endTime time.Time) {
+	fmt.Printf("🔧 Worker %d started (%s)\n", workerID, category)
+	
+	// Desynchronize workers started at the same moment
+	jitter := startJitter(lg.startJitter)
+	lg.debugf("Worker %d start jitter %v", workerID, jitter.Round(time.Millisecond))
+	time.Sleep(jitter)
 	
-	for time.Now().Before(endTime) {
+	for (lg.budget != nil || time.Now().Before(endTime)) && !lg.inFlight.Stopping() {
+		// Back off quietly while the target's circuit is open
+		if lg.breakers.For(lg.target).Blocked() {
+			time.Sleep(500 * time.Millisecond)
+			continue
+		}
+		// Every request takes from the budget as it is sent, fallbacks,
+		// read-backs and scenario steps included
+		if lg.budget.Exhausted() {
+			break
+		}
+
+		// Scenarios walk one item through its whole lifecycle per iteration
+		if lg.mode == modeScenario && !lg.draining.Load() {
+			lg.runScenario()
+			time.Sleep(lg.thinkTime.Next())
+			continue
+		}
+
 		// Randomly choose an operation
-		operation := lg.chooseOperation()
+		operation := lg.chooseOperation(category)
 		
 		switch operation {
 		case "health":
@@ -150,19 +426,62 @@ func (lg *LoadGenerator) worker(workerID int, endTime time.Time) {
 			lg.doGetItem()
 		case "update":
 			lg.doUpdateItem()
+		case "patch":
+			lg.doPatchItem()
+		case "search":
+			lg.doSearchItems()
 		case "delete":
 			lg.doDeleteItem()
 		}
 		
-		// Random delay between requests (100ms to 2s)
-		delay := time.Duration(rand.Intn(1900)+100) * time.Millisecond
-		time.Sleep(delay)
+		// Think time between requests, uniform 100ms to 2s by default
+		time.Sleep(lg.thinkTime.Next())
 	}
 	
 	fmt.Printf("🏁 Worker %d finished\n", workerID)
 }
 
-func (lg *LoadGenerator) chooseOperation() string {
+func (lg *LoadGenerator) chooseOperation(category string) string {
+	operation := lg.weightedOperation(category)
+	// With PATCH enabled half of the updates become partial updates
+	if operation == "update" && lg.patchEnabled && rng.Intn(2) == 0 {
+		return "patch"
+	}
+	// With search enabled a third of the listings become searches
+	if operation == "list" && lg.searchEnabled && rng.Intn(3) == 0 {
+		return "search"
+	}
+	return operation
+}
+
+func (lg *LoadGenerator) weightedOperation(category string) string {
+	// Health mode probes availability without touching items
+	if lg.mode == modeHealth {
+		return "health"
+	}
+
+	// While draining only read traffic is generated, write workers sit idle
+	if lg.draining.Load() {
+		if category == categoryWrite {
+			return ""
+		}
+		readOperations := []string{"health", "list", "get"}
+		return readOperations[rng.Intn(len(readOperations))]
+	}
+
+	// Dedicated pools keep the mixed pool's relative weights within their category
+	switch category {
+	case categoryRead:
+		operations := []string{"health", "health", "health", "list", "list", "list", "get", "get"}
+		return operations[rng.Intn(len(operations))]
+	case categoryWrite:
+		operations := []string{"create", "create", "update", "delete"}
+		if lg.itemIDs.Len() == 0 {
+			operations[len(operations)-1] = "create"
+		}
+		return operations[rng.Intn(len(operations))]
+	}
+
 	// Weighted random selection to create realistic traffic patterns
 	operations := []string{
 		"health", "health", "health",  // 30% health checks
@@ -174,153 +493,190 @@ func (lg *LoadGenerator) chooseOperation() string {
 	}
 	
 	// Don't delete if we have no items
-	if len(lg.itemIDs) == 0 {
+	if lg.itemIDs.Len() == 0 {
 		operations = append(operations[:len(operations)-1], "create")
 	}
 	
-	return operations[rand.Intn(len(operations))]
+	return operations[rng.Intn(len(operations))]
 }
 
 func (lg *LoadGenerator) doHealthCheck() {
-	lg.stats.TotalRequests++
-	lg.stats.HealthCount++
+	if !lg.budget.Take() {
+		return
+	}
+	lg.stats.TotalRequests.Add(1)
+	lg.stats.HealthCount.Add(1)
 	
+	start := time.Now()
 	resp, err := lg.client.Get(lg.baseURL + "/health")
+	lg.healthLatency.Record(time.Since(start))
 	if err != nil {
-		lg.stats.FailedRequests++
+		lg.stats.FailedRequests.Add(1)
 		fmt.Printf("❌ Health check failed: %v\n", err)
 		return
 	}
 	defer resp.Body.Close()
 	
 	if resp.StatusCode == 200 {
-		lg.stats.SuccessRequests++
+		lg.stats.SuccessRequests.Add(1)
 		fmt.Printf("✅ Health check OK\n")
 	} else {
-		lg.stats.FailedRequests++
+		lg.stats.FailedRequests.Add(1)
 		fmt.Printf("⚠️  Health check returned %d\n", resp.StatusCode)
 	}
 }
 
 func (lg *LoadGenerator) doCreateItem() {
-	lg.stats.TotalRequests++
-	lg.stats.CreateCount++
+	// Let the store shrink while it is above MAX_STORE_ITEMS
+	if lg.createsPaused.Load() {
+		if lg.itemIDs.Len() > 0 {
+			lg.doDeleteItem()
+		} else {
+			lg.doListItems()
+		}
+		return
+	}
+
+	if !lg.budget.Take() {
+		return
+	}
+	lg.stats.TotalRequests.Add(1)
+	lg.stats.CreateCount.Add(1)
 	
 	// Generate random item data
 	item := Item{
-		Name:        fmt.Sprintf("Load Test Item %d", rand.Intn(10000)),
-		Description: fmt.Sprintf("Generated by load test at %s", time.Now().Format("15:04:05")),
+		Name:        lg.names.Next("Load Test Item"),
+		Description: lg.descriptions.Next(fmt.Sprintf("Generated by load test at %s", time.Now().Format("15:04:05"))),
+	}
+	if lg.searchEnabled {
+		item.Metadata = map[string]any{searchMetadataKey: item.Name}
 	}
 	
 	jsonData, _ := json.Marshal(item)
 	resp, err := lg.client.Post(lg.baseURL+"/api/v1/items", "application/json", bytes.NewBuffer(jsonData))
 	if err != nil {
-		lg.stats.FailedRequests++
+		lg.stats.FailedRequests.Add(1)
 		fmt.Printf("❌ Create item failed: %v\n", err)
 		return
 	}
 	defer resp.Body.Close()
 	
 	if resp.StatusCode == 201 {
-		lg.stats.SuccessRequests++
+		lg.stats.SuccessRequests.Add(1)
 		
 		// Parse response to get item ID
 		var createdItem Item
 		body, _ := io.ReadAll(resp.Body)
 		if json.Unmarshal(body, &createdItem) == nil {
-			lg.itemIDs = append(lg.itemIDs, createdItem.ID)
+			lg.itemIDs.Add(createdItem.ID)
 			fmt.Printf("✅ Created item: %s\n", createdItem.Name)
+			lg.searchTerms.Add(createdItem.Name)
+			if lg.verify {
+				lg.verifyItem(createdItem.ID, createdItem)
+			}
 		}
 	} else {
-		lg.stats.FailedRequests++
+		lg.stats.FailedRequests.Add(1)
 		fmt.Printf("⚠️  Create item returned %d\n", resp.StatusCode)
 	}
 }
 
 func (lg *LoadGenerator) doListItems() {
-	lg.stats.TotalRequests++
-	lg.stats.ReadCount++
+	if !lg.budget.Take() {
+		return
+	}
+	lg.stats.TotalRequests.Add(1)
+	lg.stats.ReadCount.Add(1)
 	
 	resp, err := lg.client.Get(lg.baseURL + "/api/v1/items")
 	if err != nil {
-		lg.stats.FailedRequests++
+		lg.stats.FailedRequests.Add(1)
 		fmt.Printf("❌ List items failed: %v\n", err)
 		return
 	}
 	defer resp.Body.Close()
 	
 	if resp.StatusCode == 200 {
-		lg.stats.SuccessRequests++
+		lg.stats.SuccessRequests.Add(1)
 		
 		// Parse response to update our item IDs
 		var itemsResp ItemsResponse
 		body, _ := io.ReadAll(resp.Body)
 		if json.Unmarshal(body, &itemsResp) == nil {
 			// Update our item IDs list
-			lg.itemIDs = make([]string, 0, len(itemsResp.Items))
+			ids := make([]string, 0, len(itemsResp.Items))
 			for _, item := range itemsResp.Items {
-				lg.itemIDs = append(lg.itemIDs, item.ID)
+				ids = append(ids, item.ID)
 			}
+			lg.itemIDs.Replace(ids)
 			fmt.Printf("✅ Listed %d items\n", itemsResp.Total)
 		}
 	} else {
-		lg.stats.FailedRequests++
+		lg.stats.FailedRequests.Add(1)
 		fmt.Printf("⚠️  List items returned %d\n", resp.StatusCode)
 	}
 }
 
 func (lg *LoadGenerator) doGetItem() {
-	if len(lg.itemIDs) == 0 {
+	itemID, ok := lg.itemIDs.Pick()
+	if !ok {
+		if lg.draining.Load() {
+			lg.doListItems()
+			return
+		}
 		// No items to get, create one first
 		lg.doCreateItem()
 		return
 	}
 	
-	lg.stats.TotalRequests++
-	lg.stats.ReadCount++
+	if !lg.budget.Take() {
+		return
+	}
+	lg.stats.TotalRequests.Add(1)
+	lg.stats.ReadCount.Add(1)
 	
-	// Get random item
-	itemID := lg.itemIDs[rand.Intn(len(lg.itemIDs))]
 	
 	resp, err := lg.client.Get(lg.baseURL + "/api/v1/items/" + itemID)
 	if err != nil {
-		lg.stats.FailedRequests++
+		lg.stats.FailedRequests.Add(1)
 		fmt.Printf("❌ Get item failed: %v\n", err)
 		return
 	}
 	defer resp.Body.Close()
 	
 	if resp.StatusCode == 200 {
-		lg.stats.SuccessRequests++
-		fmt.Printf("✅ Retrieved item: %s\n", itemID[:8]+"...")
+		lg.stats.SuccessRequests.Add(1)
+		fmt.Printf("✅ Retrieved item: %s\n", shortID(itemID))
 	} else if resp.StatusCode == 404 {
-		lg.stats.FailedRequests++
-		fmt.Printf("⚠️  Item not found: %s\n", itemID[:8]+"...")
+		lg.stats.FailedRequests.Add(1)
+		fmt.Printf("⚠️  Item not found: %s\n", shortID(itemID))
 		// Remove from our list
 		lg.removeItemID(itemID)
 	} else {
-		lg.stats.FailedRequests++
+		lg.stats.FailedRequests.Add(1)
 		fmt.Printf("⚠️  Get item returned %d\n", resp.StatusCode)
 	}
 }
 
 func (lg *LoadGenerator) doUpdateItem() {
-	if len(lg.itemIDs) == 0 {
+	itemID, ok := lg.itemIDs.Pick()
+	if !ok {
 		// No items to update, create one first
 		lg.doCreateItem()
 		return
 	}
 	
-	lg.stats.TotalRequests++
-	lg.stats.UpdateCount++
+	if !lg.budget.Take() {
+		return
+	}
+	lg.stats.TotalRequests.Add(1)
+	lg.stats.UpdateCount.Add(1)
 	
-	// Get random item
-	itemID := lg.itemIDs[rand.Intn(len(lg.itemIDs))]
 	
 	// Generate updated data
 	item := Item{
-		Name:        fmt.Sprintf("Updated Item %d", rand.Intn(10000)),
-		Description: fmt.Sprintf("Updated by load test at %s", time.Now().Format("15:04:05")),
+		Name:        lg.names.Next("Updated Item"),
+		Description: lg.descriptions.Next(fmt.Sprintf("Updated by load test at %s", time.Now().Format("15:04:05"))),
 	}
 	
 	jsonData, _ := json.Marshal(item)
@@ -329,68 +685,116 @@ func (lg *LoadGenerator) doUpdateItem() {
 	
 	resp, err := lg.client.Do(req)
 	if err != nil {
-		lg.stats.FailedRequests++
+		lg.stats.FailedRequests.Add(1)
 		fmt.Printf("❌ Update item failed: %v\n", err)
 		return
 	}
 	defer resp.Body.Close()
 	
 	if resp.StatusCode == 200 {
-		lg.stats.SuccessRequests++
-		fmt.Printf("✅ Updated item: %s\n", itemID[:8]+"...")
+		lg.stats.SuccessRequests.Add(1)
+		fmt.Printf("✅ Updated item: %s\n", shortID(itemID))
+		var updated Item
+		body, _ := io.ReadAll(resp.Body)
+		if lg.verify && json.Unmarshal(body, &updated) == nil {
+			lg.verifyItem(itemID, updated)
+		}
 	} else if resp.StatusCode == 404 {
-		lg.stats.FailedRequests++
-		fmt.Printf("⚠️  Item not found for update: %s\n", itemID[:8]+"...")
+		lg.stats.FailedRequests.Add(1)
+		fmt.Printf("⚠️  Item not found for update: %s\n", shortID(itemID))
 		lg.removeItemID(itemID)
 	} else {
-		lg.stats.FailedRequests++
+		lg.stats.FailedRequests.Add(1)
 		fmt.Printf("⚠️  Update item returned %d\n", resp.StatusCode)
 	}
 }
 
+// doPatchItem sends a partial update changing either the name or the description
+func (lg *LoadGenerator) doPatchItem() {
+	itemID, ok := lg.itemIDs.Pick()
+	if !ok {
+		// No items to patch, create one first
+		lg.doCreateItem()
+		return
+	}
+	
+	if !lg.budget.Take() {
+		return
+	}
+	lg.stats.TotalRequests.Add(1)
+	lg.stats.PatchCount.Add(1)
+	
+	
+	patch := map[string]string{"name": lg.names.Next("Patched Item")}
+	if rng.Intn(2) == 0 {
+		patch = map[string]string{"description": lg.descriptions.Next(fmt.Sprintf("Patched by load test at %s", time.Now().Format("15:04:05")))}
+	}
+	
+	jsonData, _ := json.Marshal(patch)
+	req, _ := http.NewRequest("PATCH", lg.baseURL+"/api/v1/items/"+itemID, bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	
+	resp, err := lg.client.Do(req)
+	if err != nil {
+		lg.stats.FailedRequests.Add(1)
+		fmt.Printf("❌ Patch item failed: %v\n", err)
+		return
+	}
+	defer resp.Body.Close()
+	
+	if resp.StatusCode == 200 {
+		lg.stats.SuccessRequests.Add(1)
+		fmt.Printf("✅ Patched item: %s\n", shortID(itemID))
+	} else if resp.StatusCode == 404 {
+		lg.stats.FailedRequests.Add(1)
+		fmt.Printf("⚠️  Item not found for patch: %s\n", shortID(itemID))
+		lg.removeItemID(itemID)
+	} else {
+		lg.stats.FailedRequests.Add(1)
+		fmt.Printf("⚠️  Patch item returned %d\n", resp.StatusCode)
+	}
+}
+
 func (lg *LoadGenerator) doDeleteItem() {
-	if len(lg.itemIDs) == 0 {
+	itemID, ok := lg.itemIDs.Pick()
+	if !ok {
 		// No items to delete, create one first
 		lg.doCreateItem()
 		return
 	}
 	
-	lg.stats.TotalRequests++
-	lg.stats.DeleteCount++
+	if !lg.budget.Take() {
+		return
+	}
+	lg.stats.TotalRequests.Add(1)
+	lg.stats.DeleteCount.Add(1)
 	
-	// Get random item
-	itemID := lg.itemIDs[rand.Intn(len(lg.itemIDs))]
 	
 	req, _ := http.NewRequest("DELETE", lg.baseURL+"/api/v1/items/"+itemID, nil)
 	resp, err := lg.client.Do(req)
 	if err != nil {
-		lg.stats.FailedRequests++
+		lg.stats.FailedRequests.Add(1)
 		fmt.Printf("❌ Delete item failed: %v\n", err)
 		return
 	}
 	defer resp.Body.Close()
 	
 	if resp.StatusCode == 200 {
-		lg.stats.SuccessRequests++
-		fmt.Printf("✅ Deleted item: %s\n", itemID[:8]+"...")
+		lg.stats.SuccessRequests.Add(1)
+		fmt.Printf("✅ Deleted item: %s\n", shortID(itemID))
 		lg.removeItemID(itemID)
 	} else if resp.StatusCode == 404 {
-		lg.stats.FailedRequests++
-		fmt.Printf("⚠️  Item not found for delete: %s\n", itemID[:8]+"...")
+		lg.stats.FailedRequests.Add(1)
+		fmt.Printf("⚠️  Item not found for delete: %s\n", shortID(itemID))
 		lg.removeItemID(itemID)
 	} else {
-		lg.stats.FailedRequests++
+		lg.stats.FailedRequests.Add(1)
 		fmt.Printf("⚠️  Delete item returned %d\n", resp.StatusCode)
 	}
 }
 
 func (lg *LoadGenerator) removeItemID(itemID string) {
-	for i, id := range lg.itemIDs {
-		if id == itemID {
-			lg.itemIDs = append(lg.itemIDs[:i], lg.itemIDs[i+1:]...)
-			break
-		}
-	}
+	lg.itemIDs.Remove(itemID)
 }
 
 func (lg *LoadGenerator) reportStats() {
@@ -399,29 +803,70 @@ func (lg *LoadGenerator) reportStats() {
 	
 	for range ticker.C {
 		fmt.Printf("\n📊 Stats Update:\n")
-		fmt.Printf("   Total Requests: %d\n", lg.stats.TotalRequests)
-		fmt.Printf("   Success: %d, Failed: %d\n", lg.stats.SuccessRequests, lg.stats.FailedRequests)
-		fmt.Printf("   Creates: %d, Reads: %d, Updates: %d, Deletes: %d, Health: %d\n",
-			lg.stats.CreateCount, lg.stats.ReadCount, lg.stats.UpdateCount, lg.stats.DeleteCount, lg.stats.HealthCount)
-		fmt.Printf("   Active Items: %d\n\n", len(lg.itemIDs))
+		fmt.Printf("   Total Requests: %d\n", lg.stats.TotalRequests.Load())
+		fmt.Printf("   Success: %d, Failed: %d\n", lg.stats.SuccessRequests.Load(), lg.stats.FailedRequests.Load())
+		fmt.Printf("   Status Codes: %s\n", lg.statuses.String())
+		fmt.Printf("   Creates: %d, Reads: %d, Searches: %d, Updates: %d, Patches: %d, Deletes: %d, Health: %d\n",
+			lg.stats.CreateCount.Load(), lg.stats.ReadCount.Load(), lg.stats.SearchCount.Load(), lg.stats.UpdateCount.Load(), lg.stats.PatchCount.Load(), lg.stats.DeleteCount.Load(), lg.stats.HealthCount.Load())
+		if lg.verify {
+			fmt.Printf("   Consistency Errors: %d (%d skipped after concurrent writes)\n", lg.stats.ConsistencyErrors.Load(), lg.stats.VerifySkipped.Load())
+		}
+		fmt.Printf("   Active Items: %d\n\n", lg.itemIDs.Len())
 	}
 }
 
 func (lg *LoadGenerator) printFinalStats() {
 	fmt.Printf("\n📊 Final Statistics:\n")
 	fmt.Printf("===================\n")
-	fmt.Printf("Total Requests: %d\n", lg.stats.TotalRequests)
-	fmt.Printf("Successful: %d (%.1f%%)\n", lg.stats.SuccessRequests, 
-		float64(lg.stats.SuccessRequests)/float64(lg.stats.TotalRequests)*100)
-	fmt.Printf("Failed: %d (%.1f%%)\n", lg.stats.FailedRequests,
-		float64(lg.stats.FailedRequests)/float64(lg.stats.TotalRequests)*100)
+	fmt.Printf("Total Requests: %d\n", lg.stats.TotalRequests.Load())
+	if lg.endedBy != "" {
+		fmt.Printf("Ended By: %s\n", lg.endedBy)
+	}
+	fmt.Printf("Hard Stop: %s\n", lg.hardStop)
+	// Runs interrupted before the first request have nothing to divide by
+	if lg.stats.TotalRequests.Load() == 0 {
+		fmt.Printf("No requests made\n")
+	} else {
+		fmt.Printf("Successful: %d (%s)\n", lg.stats.SuccessRequests.Load(), percentOf(lg.stats.SuccessRequests.Load(), lg.stats.TotalRequests.Load()))
+		fmt.Printf("Failed: %d (%s)\n", lg.stats.FailedRequests.Load(), percentOf(lg.stats.FailedRequests.Load(), lg.stats.TotalRequests.Load()))
+	}
+	// Every response counts here, read-backs and store size checks included
+	fmt.Printf("Status Codes: %s\n", &lg.statuses)
 	fmt.Printf("\nOperation Breakdown:\n")
-	fmt.Printf("  Creates: %d\n", lg.stats.CreateCount)
-	fmt.Printf("  Reads: %d\n", lg.stats.ReadCount)
-	fmt.Printf("  Updates: %d\n", lg.stats.UpdateCount)
-	fmt.Printf("  Deletes: %d\n", lg.stats.DeleteCount)
-	fmt.Printf("  Health Checks: %d\n", lg.stats.HealthCount)
-	fmt.Printf("\nItems remaining: %d\n", len(lg.itemIDs))
+	fmt.Printf("  Creates: %d\n", lg.stats.CreateCount.Load())
+	fmt.Printf("  Reads: %d\n", lg.stats.ReadCount.Load())
+	if lg.searchEnabled {
+		fmt.Printf("  Searches: %d (%d hits, %d misses, %s hit rate)\n", lg.stats.SearchCount.Load(), lg.stats.SearchHits.Load(), lg.stats.SearchMisses.Load(),
+			percentOf(lg.stats.SearchHits.Load(), lg.stats.SearchHits.Load()+lg.stats.SearchMisses.Load()))
+	}
+	fmt.Printf("  Updates: %d\n", lg.stats.UpdateCount.Load())
+	fmt.Printf("  Patches: %d\n", lg.stats.PatchCount.Load())
+	fmt.Printf("  Deletes: %d\n", lg.stats.DeleteCount.Load())
+	fmt.Printf("  Health Checks: %d\n", lg.stats.HealthCount.Load())
+	if lg.verify {
+		fmt.Printf("  Consistency Errors: %d (%d skipped after concurrent writes)\n", lg.stats.ConsistencyErrors.Load(), lg.stats.VerifySkipped.Load())
+	}
+	if lg.stats.CreatePauses.Load() > 0 {
+		fmt.Printf("  Create Pauses: %d\n", lg.stats.CreatePauses.Load())
+	}
+	if lg.mode == modeScenario {
+		fmt.Printf("  Scenarios: %d (%d failed)\n", lg.stats.ScenarioCount.Load(), lg.stats.ScenarioFailures.Load())
+	}
+	if elapsed := lg.endedAt.Sub(lg.startedAt).Seconds(); !lg.startedAt.IsZero() && elapsed > 0 {
+		reads := lg.stats.ReadCount.Load() + lg.stats.SearchCount.Load() + lg.stats.HealthCount.Load()
+		writes := lg.stats.CreateCount.Load() + lg.stats.UpdateCount.Load() + lg.stats.PatchCount.Load() + lg.stats.DeleteCount.Load()
+		fmt.Printf("\nThroughput:\n")
+		fmt.Printf("  Read: %.2f req/s\n", float64(reads)/elapsed)
+		fmt.Printf("  Write: %.2f req/s\n", float64(writes)/elapsed)
+	}
+	lg.printHealthLatency()
+	fmt.Printf("\nIn flight at shutdown: %d (completed %d, cancelled %d)\n",
+		lg.stats.InFlightAtStop.Load(), lg.inFlight.completed.Load(), lg.inFlight.cancelled.Load())
+	fmt.Printf("Items remaining: %d\n", lg.itemIDs.Len())
+	for _, b := range lg.breakers.Breakers() {
+		fmt.Printf("Circuit breaker %s: opened %d times, %v open\n",
+			b.target, b.Trips(), b.OpenTime().Round(time.Millisecond))
+	}
 	fmt.Printf("\n🎯 Check your observability stack:\n")
 	fmt.Printf("   - Traces in Tempo/Grafana\n")
 	fmt.Printf("   - Logs in Loki/Grafana\n")
@@ -429,6 +874,24 @@ func (lg *LoadGenerator) printFinalStats() {
 }
 
 // Helper functions
+
+// percentOf formats n as a percentage of total, "n/a" when total is zero
+func percentOf(n, total int64) string {
+	if total == 0 {
+		return "n/a"
+	}
+	return fmt.Sprintf("%.1f%%", float64(n)/float64(total)*100)
+}
+
+// shortID abbreviates long IDs such as UUIDs for log lines, leaving short
+// ones such as ID_STRATEGY=sequential IDs whole
+func shortID(id string) string {
+	if len(id) <= 8 {
+		return id
+	}
+	return id[:8] + "..."
+}
+
 func getEnv(key, fallback string) string {
 	if value, exists := os.LookupEnv(key); exists {
 		return value
@@ -436,22 +899,22 @@ func getEnv(key, fallback string) string {
 	return fallback
 }
 
-func parseDuration(s string) time.Duration {
+func parseDuration(s string, fallback time.Duration) time.Duration {
 	d, err := time.ParseDuration(s)
 	if err != nil {
-		return defaultDuration
+		return fallback
 	}
 	return d
 }
 
-func parseInt(s string) int {
+func parseInt(s string, fallback int) int {
 	if s == "" {
-		return defaultConcurrency
+		return fallback
 	}
 	var i int
 	fmt.Sscanf(s, "%d", &i)
 	if i <= 0 {
-		return defaultConcurrency
+		return fallback
 	}
 	return i
 }
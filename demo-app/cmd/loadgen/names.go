@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+)
+
+const (
+	nameRandom  = "random"
+	nameUniform = "uniform"
+	nameZipf    = "zipf"
+
+	defaultNameVocabSize = 50
+)
+
+var (
+	nameAdjectives = []string{"Red", "Blue", "Green", "Small", "Large", "Smart", "Classic", "Deluxe"}
+	nameNouns      = []string{"Widget", "Gadget", "Sprocket", "Gizmo", "Lamp", "Chair", "Kettle", "Router"}
+)
+
+// nameGenerator picks item names. The random distribution keeps the old
+// near-unique names; uniform and zipf draw from a fixed vocabulary so names
+// recur, with zipf making a few names far more common than the rest.
+type nameGenerator struct {
+	dist  string
+	vocab []string
+
+	mu   sync.Mutex
+	zipf *rand.Zipf
+}
+
+// newNameGenerator validates the distribution name, falling back to random
+func newNameGenerator(dist string, vocabSize int) *nameGenerator {
+	switch dist {
+	case nameRandom, nameUniform, nameZipf:
+	default:
+		fmt.Printf("⚠️  Unknown NAME_DISTRIBUTION %q, using %s\n", dist, nameRandom)
+		dist = nameRandom
+	}
+
+	g := &nameGenerator{dist: dist}
+	if dist == nameRandom {
+		return g
+	}
+	g.vocab = make([]string, vocabSize)
+	for i := range g.vocab {
+		g.vocab[i] = vocabName(i)
+	}
+	if dist == nameZipf {
+		// s=1.1 gives the usual long tail: the top name is roughly twice as
+		// common as the second, and most of the vocabulary is rare
+		g.zipf = rand.NewZipf(rand.New(rand.NewSource(rng.Int63())), 1.1, 1, uint64(vocabSize-1))
+	}
+	return g
+}
+
+// vocabName builds the i-th vocabulary entry from adjective and noun pairs,
+// numbering them once every pair is used
+func vocabName(i int) string {
+	pairs := len(nameAdjectives) * len(nameNouns)
+	name := nameAdjectives[i%len(nameAdjectives)] + " " + nameNouns[(i/len(nameAdjectives))%len(nameNouns)]
+	if round := i / pairs; round > 0 {
+		name = fmt.Sprintf("%s %d", name, round+1)
+	}
+	return name
+}
+
+// Next returns a name, prefix is only used by the random distribution
+func (g *nameGenerator) Next(prefix string) string {
+	switch g.dist {
+	case nameUniform:
+		return g.vocab[rng.Intn(len(g.vocab))]
+	case nameZipf:
+		// rand.Zipf is not safe for concurrent use
+		g.mu.Lock()
+		i := g.zipf.Uint64()
+		g.mu.Unlock()
+		return g.vocab[i]
+	}
+	return fmt.Sprintf("%s %d", prefix, rng.Intn(10000))
+}
+
+func (g *nameGenerator) String() string {
+	if g.dist == nameRandom {
+		return nameRandom
+	}
+	return fmt.Sprintf("%s over %d names", g.dist, len(g.vocab))
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+const defaultProgressInterval = 5 * time.Second
+
+// reportProgress prints elapsed/total time, completion, current request
+// rate, running success rate and responses by status code every interval
+// until the run ends. Runs capped by
+// MAX_REQUESTS report requests sent instead of time. On a terminal the line
+// is rewritten in place, otherwise each update is its own line so logs stay
+// readable.
+func (lg *LoadGenerator) reportProgress(duration, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	tty := isTerminal(os.Stdout)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	lastTotal, lastTick := int64(0), lg.startedAt
+	for now := range ticker.C {
+		elapsed := now.Sub(lg.startedAt)
+		if elapsed > duration {
+			elapsed = duration
+		}
+		percent := float64(elapsed) / float64(duration) * 100
+
+		total := lg.stats.TotalRequests.Load()
+		rps := float64(total-lastTotal) / now.Sub(lastTick).Seconds()
+		lastTotal, lastTick = total, now
+
+		success := 100.0
+		if total > 0 {
+			success = float64(lg.stats.SuccessRequests.Load()) / float64(total) * 100
+		}
+
+		line := fmt.Sprintf("⏱️  %s %v/%v (%.0f%%) | %.1f req/s | %.1f%% success | ETA %v",
+			progressBar(percent, 20), elapsed.Round(time.Second), duration, percent, rps, success,
+			(duration - elapsed).Round(time.Second))
+		if lg.budget != nil {
+			// MAX_REQUESTS runs progress by count, not time
+			used := lg.budget.Used()
+			percent = float64(used) / float64(lg.budget.max) * 100
+			line = fmt.Sprintf("⏱️  %s %d/%d requests (%.0f%%) | %.1f req/s | %.1f%% success | %v elapsed",
+				progressBar(percent, 20), used, lg.budget.max, percent, rps, success, now.Sub(lg.startedAt).Round(time.Second))
+		}
+		line += " | " + lg.statuses.String()
+		if tty {
+			fmt.Printf("\r\033[K%s", line)
+		} else {
+			fmt.Println(line)
+		}
+	}
+}
+
+// progressBar renders percent as a fixed width bar
+func progressBar(percent float64, width int) string {
+	filled := int(percent / 100 * float64(width))
+	filled = min(max(filled, 0), width)
+	return "[" + strings.Repeat("#", filled) + strings.Repeat("-", width-filled) + "]"
+}
+
+// isTerminal reports whether f is a character device such as a TTY
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// defaultScenarioBaggageKey is the baggage member carrying the scenario ID,
+// matching the server's SCENARIO_BAGGAGE_KEY default
+const defaultScenarioBaggageKey = "scenario.id"
+
+// scenarioBaggageKey reads SCENARIO_BAGGAGE_KEY, falling back to the default
+// for keys baggage cannot carry
+func scenarioBaggageKey() string {
+	key := getEnv("SCENARIO_BAGGAGE_KEY", defaultScenarioBaggageKey)
+	if _, err := baggage.NewMember(key, "x"); err != nil {
+		fmt.Printf("⚠️  Invalid SCENARIO_BAGGAGE_KEY %q, using %s: %v\n", key, defaultScenarioBaggageKey, err)
+		return defaultScenarioBaggageKey
+	}
+	return key
+}
+
+// runScenario walks one item through create, read, update and delete. The
+// iteration is a parent span with a client span per step, each linked to the
+// step before it, and the trace context is sent along so the server's spans
+// join the same trace. A random scenario ID goes along as baggage so the
+// server's spans and logs of the run can be found without tracing too.
+// Each step takes from MAX_REQUESTS; a spent budget ends the scenario early
+// without counting it as failed.
+func (lg *LoadGenerator) runScenario() {
+	if !lg.budget.Take() {
+		return
+	}
+	lg.stats.ScenarioCount.Add(1)
+
+	scenarioID := fmt.Sprintf("%016x", rng.Uint64())
+	ctx := context.Background()
+	if member, err := baggage.NewMember(lg.scenarioKey, scenarioID); err == nil {
+		bag, _ := baggage.New(member)
+		ctx = baggage.ContextWithBaggage(ctx, bag)
+	}
+
+	ctx, span := tracer.Start(ctx, "scenario.item_lifecycle", trace.WithAttributes(attribute.String("scenario.id", scenarioID)))
+	defer span.End()
+
+	fail := func(step string, err error) {
+		lg.stats.ScenarioFailures.Add(1)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, step+" failed")
+		span.SetAttributes(attribute.String("scenario.failed_step", step))
+		fmt.Printf("❌ Scenario %s %s failed: %v\n", scenarioID, step, err)
+	}
+	// next reserves the request of a later step
+	next := func(step string) bool {
+		if lg.budget.Take() {
+			return true
+		}
+		span.SetAttributes(attribute.String("scenario.budget_spent_at", step))
+		return false
+	}
+
+	// Create
+	lg.stats.CreateCount.Add(1)
+	var created Item
+	prev, body, err := lg.scenarioStep(ctx, trace.SpanContext{}, "create", "POST", "/api/v1/items", Item{
+		Name:        lg.names.Next("Scenario Item"),
+		Description: lg.descriptions.Next(fmt.Sprintf("Generated by load test scenario at %s", time.Now().Format("15:04:05"))),
+	}, http.StatusCreated)
+	if err == nil {
+		err = json.Unmarshal(body, &created)
+	}
+	if err != nil {
+		fail("create", err)
+		return
+	}
+	span.SetAttributes(attribute.String("item.id", created.ID))
+	path := "/api/v1/items/" + created.ID
+
+	// Read
+	if !next("read") {
+		return
+	}
+	lg.stats.ReadCount.Add(1)
+	if prev, _, err = lg.scenarioStep(ctx, prev, "read", "GET", path, nil, http.StatusOK); err != nil {
+		fail("read", err)
+		return
+	}
+
+	// Update
+	if !next("update") {
+		return
+	}
+	lg.stats.UpdateCount.Add(1)
+	if prev, _, err = lg.scenarioStep(ctx, prev, "update", "PUT", path, Item{
+		Name:        lg.names.Next("Updated Scenario Item"),
+		Description: lg.descriptions.Next(fmt.Sprintf("Updated by load test scenario at %s", time.Now().Format("15:04:05"))),
+	}, http.StatusOK); err != nil {
+		fail("update", err)
+		return
+	}
+
+	// Delete
+	if !next("delete") {
+		return
+	}
+	lg.stats.DeleteCount.Add(1)
+	if _, _, err = lg.scenarioStep(ctx, prev, "delete", "DELETE", path, nil, http.StatusOK); err != nil {
+		fail("delete", err)
+		return
+	}
+
+	fmt.Printf("✅ Scenario %s completed for item: %s\n", scenarioID, created.ID)
+}
+
+// scenarioStep sends one scenario request as a client span under ctx, linked
+// to the previous step when there is one. It returns the step's span context
+// for the next link and the response body, or an error unless the response
+// had status want.
+func (lg *LoadGenerator) scenarioStep(ctx context.Context, prev trace.SpanContext, step, method, path string, payload any, want int) (trace.SpanContext, []byte, error) {
+	opts := []trace.SpanStartOption{
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("scenario.step", step),
+			attribute.String("http.method", method),
+			attribute.String("http.url", lg.baseURL+path),
+		),
+	}
+	if prev.IsValid() {
+		opts = append(opts, trace.WithLinks(trace.Link{
+			SpanContext: prev,
+			Attributes:  []attribute.KeyValue{attribute.String("link.type", "previous_step")},
+		}))
+	}
+	ctx, span := tracer.Start(ctx, "scenario."+step, opts...)
+	defer span.End()
+
+	lg.stats.TotalRequests.Add(1)
+
+	var reqBody io.Reader
+	if payload != nil {
+		jsonData, _ := json.Marshal(payload)
+		reqBody = bytes.NewReader(jsonData)
+	}
+	req, _ := http.NewRequestWithContext(ctx, method, lg.baseURL+path, reqBody)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := lg.client.Do(req)
+	if err != nil {
+		lg.stats.FailedRequests.Add(1)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return span.SpanContext(), nil, err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+	if resp.StatusCode != want {
+		lg.stats.FailedRequests.Add(1)
+		err := fmt.Errorf("%s %s returned %d", method, path, resp.StatusCode)
+		span.SetStatus(codes.Error, err.Error())
+		return span.SpanContext(), body, err
+	}
+	lg.stats.SuccessRequests.Add(1)
+	return span.SpanContext(), body, nil
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"sync"
+)
+
+// maxSearchTerms bounds how many created names are kept as search terms
+const maxSearchTerms = 100
+
+// searchMetadataKey is the metadata key created items carry their name under,
+// so the list endpoint's metadata filter can find them by name
+const searchMetadataKey = "search_term"
+
+// searchTerms remembers recently created item names so searches can hit
+type searchTerms struct {
+	mu    sync.Mutex
+	terms []string
+	next  int
+}
+
+func (s *searchTerms) Add(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.terms) < maxSearchTerms {
+		s.terms = append(s.terms, name)
+		return
+	}
+	s.terms[s.next] = name
+	s.next = (s.next + 1) % maxSearchTerms
+}
+
+// Pick returns a remembered name, or "" when none was created yet
+func (s *searchTerms) Pick() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.terms) == 0 {
+		return ""
+	}
+	return s.terms[rng.Intn(len(s.terms))]
+}
+
+// randomTerm returns a string no generated name contains
+func randomTerm() string {
+	const letters = "bcdfghjklmnpqrstvwxz"
+	b := make([]byte, 10)
+	for i := range b {
+		b[i] = letters[rng.Intn(len(letters))]
+	}
+	return string(b)
+}
+
+// doSearchItems filters the listing on the search tag: a previously created
+// name two times out of three, expecting a hit, and a random string
+// otherwise, expecting a miss
+func (lg *LoadGenerator) doSearchItems() {
+	if !lg.budget.Take() {
+		return
+	}
+	lg.stats.TotalRequests.Add(1)
+	lg.stats.SearchCount.Add(1)
+
+	query := ""
+	if rng.Intn(3) > 0 {
+		query = lg.searchTerms.Pick()
+	}
+	if query == "" {
+		query = randomTerm()
+	}
+
+	resp, err := lg.client.Get(lg.baseURL + "/api/v1/items?metadata." + searchMetadataKey + "=" + url.QueryEscape(query))
+	if err != nil {
+		lg.stats.FailedRequests.Add(1)
+		fmt.Printf("❌ Search items failed: %v\n", err)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		lg.stats.FailedRequests.Add(1)
+		fmt.Printf("⚠️  Search items returned %d\n", resp.StatusCode)
+		return
+	}
+	lg.stats.SuccessRequests.Add(1)
+
+	var result ItemsResponse
+	body, _ := io.ReadAll(resp.Body)
+	if json.Unmarshal(body, &result) != nil {
+		return
+	}
+	if len(result.Items) > 0 {
+		lg.stats.SearchHits.Add(1)
+		fmt.Printf("✅ Search %q found %d items\n", query, len(result.Items))
+	} else {
+		lg.stats.SearchMisses.Add(1)
+		fmt.Printf("✅ Search %q found nothing\n", query)
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// rng drives every random choice of the generator: operations, item picks,
+// names and think times. It is seeded from SEED so a run can be replayed;
+// with several workers the interleaving still varies, so exact replays need
+// CONCURRENCY=1.
+var rng = rand.New(&lockedSource{src: rand.NewSource(1)})
+
+// lockedSource makes a rand.Source safe for the concurrent workers
+type lockedSource struct {
+	mu  sync.Mutex
+	src rand.Source
+}
+
+func (s *lockedSource) Int63() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Int63()
+}
+
+func (s *lockedSource) Seed(seed int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.src.Seed(seed)
+}
+
+// loadSeed reads SEED, picking a time based seed when it is unset or invalid,
+// and seeds rng with it
+func loadSeed() int64 {
+	seed := time.Now().UnixNano()
+	if value := getEnv("SEED", ""); value != "" {
+		if parsed, err := strconv.ParseInt(value, 10, 64); err == nil {
+			seed = parsed
+		} else {
+			fmt.Printf("⚠️  SEED=%q is not an integer, using %d\n", value, seed)
+		}
+	}
+	rng.Seed(seed)
+	return seed
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// statusNoResponse counts requests that got no HTTP response at all
+const statusNoResponse = "error"
+
+// statusCounts tallies responses by HTTP status code. It is safe for
+// concurrent use, as every worker records into it.
+type statusCounts struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func (s *statusCounts) add(status string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.counts == nil {
+		s.counts = make(map[string]int)
+	}
+	s.counts[status]++
+}
+
+// String lists the counts by status code, e.g. "200: 120, 404: 3", with
+// requests that got no response last
+func (s *statusCounts) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.counts) == 0 {
+		return "none"
+	}
+	statuses := make([]string, 0, len(s.counts))
+	for status := range s.counts {
+		statuses = append(statuses, status)
+	}
+	// Three digit codes sort numerically as strings, "error" after them
+	sort.Strings(statuses)
+
+	parts := make([]string, len(statuses))
+	for i, status := range statuses {
+		parts[i] = fmt.Sprintf("%s: %d", status, s.counts[status])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// statusTransport records the status of every response, or statusNoResponse
+// when the request failed before one arrived
+type statusTransport struct {
+	next     http.RoundTripper
+	statuses *statusCounts
+}
+
+func (t *statusTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		t.statuses.add(statusNoResponse)
+		return resp, err
+	}
+	t.statuses.add(strconv.Itoa(resp.StatusCode))
+	return resp, nil
+}
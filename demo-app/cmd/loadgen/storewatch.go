@@ -0,0 +1,56 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const defaultStoreCheckInterval = 10 * time.Second
+
+// watchStoreSize polls GET /api/v1/items/count every interval and pauses
+// creates while the store holds more than maxItems. Creates resume once it is
+// back under 90% of the limit so the generator does not flap at the edge.
+func (lg *LoadGenerator) watchStoreSize(maxItems int, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		count, err := lg.storeCount()
+		if err != nil {
+			fmt.Printf("⚠️  Store size check failed: %v\n", err)
+			continue
+		}
+
+		paused := lg.createsPaused.Load()
+		switch {
+		case !paused && count > maxItems:
+			lg.createsPaused.Store(true)
+			lg.stats.CreatePauses.Add(1)
+			fmt.Printf("⏸️  Store holds %d items, above MAX_STORE_ITEMS=%d: pausing creates\n", count, maxItems)
+		case paused && count < maxItems*9/10:
+			lg.createsPaused.Store(false)
+			fmt.Printf("▶️  Store down to %d items: resuming creates\n", count)
+		}
+	}
+}
+
+// storeCount asks the server how many items it holds
+func (lg *LoadGenerator) storeCount() (int, error) {
+	resp, err := lg.client.Get(lg.baseURL + "/api/v1/items/count")
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return 0, fmt.Errorf("count returned %d", resp.StatusCode)
+	}
+	var body struct {
+		Count int `json:"count"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return 0, err
+	}
+	return body.Count, nil
+}
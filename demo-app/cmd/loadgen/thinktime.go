@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	thinkTimeUniform     = "uniform"
+	thinkTimeExponential = "exponential"
+	thinkTimeFixed       = "fixed"
+)
+
+// thinkTime produces the delay a worker waits between requests
+type thinkTime struct {
+	dist string
+	min  time.Duration
+	max  time.Duration
+	mean time.Duration
+}
+
+// newThinkTime validates the distribution name, falling back to uniform
+func newThinkTime(dist string, min, max, mean time.Duration) thinkTime {
+	switch dist {
+	case thinkTimeUniform, thinkTimeExponential, thinkTimeFixed:
+	default:
+		fmt.Printf("⚠️  Unknown THINK_TIME_DIST %q, using %s\n", dist, thinkTimeUniform)
+		dist = thinkTimeUniform
+	}
+	if max < min {
+		max = min
+	}
+	return thinkTime{dist: dist, min: min, max: max, mean: mean}
+}
+
+// Next returns the next delay.
+//   - uniform: evenly spread between min and max
+//   - exponential: Poisson-like arrivals around mean, capped at 10x mean so a
+//     single unlucky draw cannot stall a worker
+//   - fixed: always mean
+func (t thinkTime) Next() time.Duration {
+	switch t.dist {
+	case thinkTimeExponential:
+		d := time.Duration(rng.ExpFloat64() * float64(t.mean))
+		if limit := 10 * t.mean; d > limit {
+			d = limit
+		}
+		return d
+	case thinkTimeFixed:
+		return t.mean
+	}
+	return t.min + time.Duration(rng.Int63n(int64(t.max-t.min)+1))
+}
+
+func (t thinkTime) String() string {
+	switch t.dist {
+	case thinkTimeExponential:
+		return fmt.Sprintf("exponential (mean %v)", t.mean)
+	case thinkTimeFixed:
+		return fmt.Sprintf("fixed (%v)", t.mean)
+	}
+	return fmt.Sprintf("uniform (%v-%v)", t.min, t.max)
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+)
+
+// loadgenServiceName identifies the generator's own spans in the backend
+const loadgenServiceName = "eks-otel-loadgen"
+
+var tracer = otel.Tracer("loadgen")
+
+// propagator sends trace context and baggage with scenario requests. It is
+// used directly rather than through the global so the scenario ID baggage
+// goes out even when tracing is off.
+var propagator = propagation.NewCompositeTextMapPropagator(
+	propagation.TraceContext{},
+	propagation.Baggage{},
+)
+
+// initTracing exports the generator's spans when OTEL_EXPORTER_OTLP_ENDPOINT
+// is set, using the exporter's standard OTEL_* variables. Without it spans
+// are dropped and only baggage is sent. The returned function flushes what
+// is left.
+func initTracing() (string, func()) {
+	endpoint := getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	if endpoint == "" {
+		return "off (set OTEL_EXPORTER_OTLP_ENDPOINT to export scenario traces)", func() {}
+	}
+
+	exporter, err := otlptracehttp.New(context.Background())
+	if err != nil {
+		fmt.Printf("⚠️  Tracing disabled, cannot create exporter: %v\n", err)
+		return "off", func() {}
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceNameKey.String(loadgenServiceName))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagator)
+
+	return "exporting to " + endpoint, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := provider.Shutdown(ctx); err != nil {
+			fmt.Printf("⚠️  Failed to flush traces: %v\n", err)
+		}
+	}
+}
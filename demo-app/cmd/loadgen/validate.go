@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultMaxConcurrency = 1000
+	maxLoadDuration       = 7 * 24 * time.Hour
+)
+
+// envCount reads a positive integer setting. Unlike parseInt it warns when a
+// value was given but is unusable, so a typo doesn't silently become the default.
+func envCount(key string, fallback int) int {
+	value := getEnv(key, "")
+	if value == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		fmt.Printf("⚠️  %s=%q is not a positive integer, using %d\n", key, value, fallback)
+		return fallback
+	}
+	return n
+}
+
+// clampConcurrency caps a worker count at limit, warning when it does
+func clampConcurrency(key string, n, limit int) int {
+	if n > limit {
+		fmt.Printf("⚠️  %s=%d exceeds MAX_CONCURRENCY, clamping to %d\n", key, n, limit)
+		return limit
+	}
+	return n
+}
+
+// loadDuration reads LOAD_DURATION, warning on unparsable values and
+// refusing to start with a non-positive or absurdly long duration
+func loadDuration() time.Duration {
+	value := getEnv("LOAD_DURATION", "")
+	if value == "" {
+		return defaultDuration
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		fmt.Printf("⚠️  LOAD_DURATION=%q is not a duration, using %v\n", value, defaultDuration)
+		return defaultDuration
+	}
+	if d <= 0 || d > maxLoadDuration {
+		log.Fatalf("❌ LOAD_DURATION=%v is out of range, must be between 0 and %v", d, maxLoadDuration)
+	}
+	return d
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// verifyItem reads an item back right after it was written and compares it
+// with what the write returned. Mismatches count as consistency errors. Other
+// workers may touch the same item meanwhile: an item that is already gone was
+// deleted and is not a mismatch, and one whose version moved past the write's
+// was written again and is skipped.
+func (lg *LoadGenerator) verifyItem(itemID string, expected Item) {
+	if !lg.budget.Take() {
+		return
+	}
+	lg.stats.TotalRequests.Add(1)
+	lg.stats.ReadCount.Add(1)
+
+	resp, err := lg.client.Get(lg.baseURL + "/api/v1/items/" + itemID)
+	if err != nil {
+		lg.stats.FailedRequests.Add(1)
+		fmt.Printf("❌ Verify item failed: %v\n", err)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		lg.stats.SuccessRequests.Add(1)
+		return
+	}
+	if resp.StatusCode != http.StatusOK {
+		lg.stats.FailedRequests.Add(1)
+		fmt.Printf("⚠️  Verify item returned %d\n", resp.StatusCode)
+		return
+	}
+	lg.stats.SuccessRequests.Add(1)
+
+	var actual Item
+	body, _ := io.ReadAll(resp.Body)
+	if err := json.Unmarshal(body, &actual); err != nil {
+		lg.stats.ConsistencyErrors.Add(1)
+		fmt.Printf("🔍 Consistency error for %s: unreadable item: %v\n", itemID, err)
+		return
+	}
+
+	if actual.Version > expected.Version {
+		lg.stats.VerifySkipped.Add(1)
+		return
+	}
+	if actual.ID != itemID || actual.Version != expected.Version || actual.Name != expected.Name || actual.Description != expected.Description {
+		lg.stats.ConsistencyErrors.Add(1)
+		fmt.Printf("🔍 Consistency error for %s: expected {id: %q, version: %d, name: %q, description: %q}, got {id: %q, version: %d, name: %q, description: %q}\n",
+			itemID, itemID, expected.Version, expected.Name, expected.Description, actual.ID, actual.Version, actual.Name, actual.Description)
+	}
+}
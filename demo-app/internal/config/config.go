@@ -0,0 +1,367 @@
+// Package config resolves the API server configuration from the environment
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/misua/eks-with-otel/demo-app/internal/middleware"
+	"github.com/misua/eks-with-otel/demo-app/internal/models"
+	"github.com/misua/eks-with-otel/demo-app/internal/storage"
+	"github.com/misua/eks-with-otel/demo-app/internal/telemetry"
+)
+
+// Config is the effective, validated configuration of the API server. The
+// README's "Server configuration" table documents every variable.
+type Config struct {
+	Port string
+
+	// Telemetry
+	OTLPEndpoint          string
+	OTELRequired          bool
+	OTELRetryInterval     time.Duration
+	BSPMaxQueueSize       int
+	BSPMaxExportBatchSize int
+	BSPExportTimeout      time.Duration
+	BSPScheduleDelay      time.Duration
+	SpanDetailLevel       string
+	SpanClientIP          bool
+	ScenarioBaggageKey    string
+	Propagators           []string
+
+	// Traffic handling
+	MaxInFlight          int
+	OverloadRetryAfter   time.Duration
+	MaxConcurrent        int
+	QueueTimeout         time.Duration
+	InjectLatency        string
+	SLOBudgets           []string
+	ChaosErrorRate       float64
+	ShutdownTimeout      time.Duration
+	MaxDecompressedBytes int
+
+	// Responses and endpoints
+	APIBasePath       string
+	BasePathProbes    bool
+	APIEnvelope       bool
+	PrettyJSON        bool
+	AdminEnabled      bool
+	SSEMaxSubscribers int
+	IdempotencyTTL    time.Duration
+	ListMaxUnpaged    int
+	HealthCheckDeep   bool
+
+	// Storage and items
+	StorageShards       int
+	HistoryLimit        int
+	StorageMaxItems     int
+	StorageFullPolicy   string
+	ReadCacheTTL        time.Duration
+	DescriptionMax      int
+	DescriptionOverflow string
+	IDStrategy          string
+	TimeFormat          string
+	MetadataKeys        []string
+	TenantAdminIDs      []string
+	SnapshotPath        string
+	SnapshotInterval    time.Duration
+}
+
+// Load reads the configuration from the environment once at startup. Unset
+// variables take their defaults; every value that is set but invalid is
+// reported in the returned error rather than silently replaced.
+func Load() (*Config, error) {
+	return load(os.LookupEnv)
+}
+
+// Defaults returns the configuration used when no variable is set, for
+// building the server in tests without touching the environment
+func Defaults() *Config {
+	c, _ := load(func(string) (string, bool) { return "", false })
+	return c
+}
+
+func load(lookup func(key string) (string, bool)) (*Config, error) {
+	e := env{lookup: lookup}
+	c := &Config{
+		Port: e.str("PORT", "8080"),
+
+		OTLPEndpoint:          e.str("OTEL_EXPORTER_OTLP_ENDPOINT", "http://otel-collector.tracing.svc.cluster.local:4318"),
+		OTELRequired:          e.boolean("OTEL_REQUIRED", false),
+		OTELRetryInterval:     e.duration("OTEL_RETRY_INTERVAL", 30*time.Second),
+		BSPMaxQueueSize:       e.integer("OTEL_BSP_MAX_QUEUE_SIZE", 4096),
+		BSPMaxExportBatchSize: e.integer("OTEL_BSP_MAX_EXPORT_BATCH_SIZE", 512),
+		BSPExportTimeout:      time.Duration(e.integer("OTEL_BSP_EXPORT_TIMEOUT", 30000)) * time.Millisecond,
+		BSPScheduleDelay:      time.Duration(e.integer("OTEL_BSP_SCHEDULE_DELAY", 5000)) * time.Millisecond,
+		SpanDetailLevel:       e.str("SPAN_DETAIL_LEVEL", telemetry.DetailFull),
+		SpanClientIP:          e.boolean("SPAN_CLIENT_IP", false),
+		ScenarioBaggageKey:    e.str("SCENARIO_BAGGAGE_KEY", middleware.DefaultScenarioBaggageKey),
+		Propagators:           e.list("OTEL_PROPAGATORS", middleware.DefaultPropagators),
+
+		MaxInFlight:          e.integer("MAX_IN_FLIGHT", 0),
+		OverloadRetryAfter:   e.duration("OVERLOAD_RETRY_AFTER", time.Second),
+		MaxConcurrent:        e.integer("MAX_CONCURRENT_REQUESTS", 0),
+		QueueTimeout:         e.duration("QUEUE_TIMEOUT", 5*time.Second),
+		InjectLatency:        e.str("INJECT_LATENCY", ""),
+		SLOBudgets:           e.list("SLO_BUDGETS", nil),
+		ChaosErrorRate:       e.float("CHAOS_ERROR_RATE", 0),
+		ShutdownTimeout:      e.duration("SHUTDOWN_TIMEOUT", 10*time.Second),
+		MaxDecompressedBytes: e.integer("MAX_DECOMPRESSED_BODY_BYTES", 10<<20),
+
+		APIBasePath:       strings.TrimSuffix(e.str("API_BASE_PATH", ""), "/"),
+		BasePathProbes:    e.boolean("API_BASE_PATH_PROBES", false),
+		APIEnvelope:       e.boolean("API_ENVELOPE", false),
+		PrettyJSON:        e.boolean("PRETTY_JSON", false),
+		AdminEnabled:      e.boolean("ADMIN_ENDPOINTS_ENABLED", false),
+		SSEMaxSubscribers: e.integer("SSE_MAX_SUBSCRIBERS", 100),
+		IdempotencyTTL:    e.duration("IDEMPOTENCY_TTL", 10*time.Minute),
+		ListMaxUnpaged:    e.integer("LIST_MAX_UNPAGED", 1000),
+		HealthCheckDeep:   e.boolean("HEALTH_CHECK_DEEP", true),
+
+		StorageShards:       e.integer("STORAGE_SHARDS", 16),
+		HistoryLimit:        e.integer("HISTORY_MAX_VERSIONS", 10),
+		StorageMaxItems:     e.integer("STORAGE_MAX_ITEMS", 0),
+		StorageFullPolicy:   e.str("STORAGE_FULL_POLICY", storage.FullPolicyReject),
+		ReadCacheTTL:        e.duration("READ_CACHE_TTL", 0),
+		DescriptionMax:      e.integer("DESCRIPTION_MAX", models.DefaultDescriptionMax),
+		DescriptionOverflow: e.str("DESCRIPTION_OVERFLOW", "reject"),
+		IDStrategy:          e.str("ID_STRATEGY", models.IDStrategyUUID),
+		TimeFormat:          e.str("TIME_FORMAT", models.TimeFormatRFC3339),
+		MetadataKeys:        e.list("METADATA_KEYS", nil),
+		TenantAdminIDs:      e.list("TENANT_ADMIN_IDS", nil),
+		SnapshotPath:        e.str("SNAPSHOT_PATH", "/tmp/items-snapshot.json"),
+		SnapshotInterval:    e.duration("SNAPSHOT_INTERVAL", 0),
+	}
+
+	errs := append(e.errs, c.validate()...)
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return c, nil
+}
+
+// validate checks ranges and allowed values of the parsed configuration
+func (c *Config) validate() []error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	port, err := strconv.Atoi(c.Port)
+	check(err == nil && port > 0 && port <= 65535, "PORT=%q must be a port number", c.Port)
+	check(strings.TrimSpace(c.OTLPEndpoint) != "", "OTEL_EXPORTER_OTLP_ENDPOINT must not be empty")
+	check(c.OTELRetryInterval >= 0, "OTEL_RETRY_INTERVAL must not be negative")
+	check(c.BSPMaxQueueSize > 0, "OTEL_BSP_MAX_QUEUE_SIZE must be positive")
+	check(c.BSPMaxExportBatchSize > 0 && c.BSPMaxExportBatchSize <= c.BSPMaxQueueSize,
+		"OTEL_BSP_MAX_EXPORT_BATCH_SIZE must be positive and at most OTEL_BSP_MAX_QUEUE_SIZE")
+	check(c.BSPExportTimeout > 0, "OTEL_BSP_EXPORT_TIMEOUT must be positive")
+	check(c.BSPScheduleDelay > 0, "OTEL_BSP_SCHEDULE_DELAY must be positive")
+	check(oneOf(c.SpanDetailLevel, telemetry.DetailMinimal, telemetry.DetailFull),
+		"SPAN_DETAIL_LEVEL=%q must be %s or %s", c.SpanDetailLevel, telemetry.DetailMinimal, telemetry.DetailFull)
+
+	_, err = middleware.NewPropagator(c.Propagators)
+	check(err == nil, "OTEL_PROPAGATORS: %v", err)
+	check(c.ScenarioBaggageKey != "", "SCENARIO_BAGGAGE_KEY must not be empty")
+
+	check(c.MaxInFlight >= 0, "MAX_IN_FLIGHT must not be negative")
+	check(c.OverloadRetryAfter >= 0, "OVERLOAD_RETRY_AFTER must not be negative")
+	check(c.MaxConcurrent >= 0, "MAX_CONCURRENT_REQUESTS must not be negative")
+	check(c.QueueTimeout > 0, "QUEUE_TIMEOUT must be positive")
+	if c.InjectLatency != "" {
+		_, _, err := middleware.ParseLatency(c.InjectLatency)
+		check(err == nil, "INJECT_LATENCY: %v", err)
+	}
+	_, err = middleware.ParseSLOBudgets(c.SLOBudgets)
+	check(err == nil, "SLO_BUDGETS: %v", err)
+	check(c.ChaosErrorRate >= 0 && c.ChaosErrorRate <= 1, "CHAOS_ERROR_RATE must be between 0 and 1")
+	check(c.ShutdownTimeout > 0, "SHUTDOWN_TIMEOUT must be positive")
+	check(c.MaxDecompressedBytes >= 0, "MAX_DECOMPRESSED_BODY_BYTES must not be negative")
+
+	check(c.APIBasePath == "" || strings.HasPrefix(c.APIBasePath, "/"), "API_BASE_PATH=%q must start with /", c.APIBasePath)
+	check(c.SSEMaxSubscribers > 0, "SSE_MAX_SUBSCRIBERS must be positive")
+	check(c.IdempotencyTTL > 0, "IDEMPOTENCY_TTL must be positive")
+	check(c.ListMaxUnpaged >= 0, "LIST_MAX_UNPAGED must not be negative")
+
+	check(c.StorageShards > 0, "STORAGE_SHARDS must be positive")
+	check(c.HistoryLimit >= 0, "HISTORY_MAX_VERSIONS must not be negative")
+	check(c.StorageMaxItems >= 0, "STORAGE_MAX_ITEMS must not be negative")
+	check(oneOf(c.StorageFullPolicy, storage.FullPolicyReject, storage.FullPolicyEvict),
+		"STORAGE_FULL_POLICY=%q must be %s or %s", c.StorageFullPolicy, storage.FullPolicyReject, storage.FullPolicyEvict)
+	check(c.ReadCacheTTL >= 0, "READ_CACHE_TTL must not be negative")
+	check(c.DescriptionMax >= 0, "DESCRIPTION_MAX must not be negative")
+	check(oneOf(c.DescriptionOverflow, "reject", "truncate"),
+		"DESCRIPTION_OVERFLOW=%q must be reject or truncate", c.DescriptionOverflow)
+	check(oneOf(c.IDStrategy, models.IDStrategyUUID, models.IDStrategyULID, models.IDStrategySequential),
+		"ID_STRATEGY=%q must be %s, %s or %s", c.IDStrategy, models.IDStrategyUUID, models.IDStrategyULID, models.IDStrategySequential)
+	check(oneOf(c.TimeFormat, models.TimeFormatRFC3339, models.TimeFormatUnixMs),
+		"TIME_FORMAT=%q must be %s or %s", c.TimeFormat, models.TimeFormatRFC3339, models.TimeFormatUnixMs)
+	check(c.SnapshotPath != "", "SNAPSHOT_PATH must not be empty")
+	check(c.SnapshotInterval >= 0, "SNAPSHOT_INTERVAL must not be negative")
+	return errs
+}
+
+func oneOf(value string, allowed ...string) bool {
+	for _, a := range allowed {
+		if value == a {
+			return true
+		}
+	}
+	return false
+}
+
+// Effective returns the configuration keyed by environment variable, with
+// durations as strings and credentials in URLs redacted, for /debug/config
+func (c *Config) Effective() map[string]any {
+	return map[string]any{
+		"PORT": c.Port,
+
+		"OTEL_EXPORTER_OTLP_ENDPOINT":    redactEndpoints(c.OTLPEndpoint),
+		"OTEL_REQUIRED":                  c.OTELRequired,
+		"OTEL_RETRY_INTERVAL":            c.OTELRetryInterval.String(),
+		"OTEL_BSP_MAX_QUEUE_SIZE":        c.BSPMaxQueueSize,
+		"OTEL_BSP_MAX_EXPORT_BATCH_SIZE": c.BSPMaxExportBatchSize,
+		"OTEL_BSP_EXPORT_TIMEOUT":        c.BSPExportTimeout.Milliseconds(),
+		"OTEL_BSP_SCHEDULE_DELAY":        c.BSPScheduleDelay.Milliseconds(),
+		"SPAN_DETAIL_LEVEL":              c.SpanDetailLevel,
+		"SPAN_CLIENT_IP":                 c.SpanClientIP,
+		"SCENARIO_BAGGAGE_KEY":           c.ScenarioBaggageKey,
+		"OTEL_PROPAGATORS":               strings.Join(c.Propagators, ","),
+
+		"MAX_IN_FLIGHT":               c.MaxInFlight,
+		"OVERLOAD_RETRY_AFTER":        c.OverloadRetryAfter.String(),
+		"MAX_CONCURRENT_REQUESTS":     c.MaxConcurrent,
+		"QUEUE_TIMEOUT":               c.QueueTimeout.String(),
+		"INJECT_LATENCY":              c.InjectLatency,
+		"SLO_BUDGETS":                 strings.Join(c.SLOBudgets, ","),
+		"CHAOS_ERROR_RATE":            c.ChaosErrorRate,
+		"SHUTDOWN_TIMEOUT":            c.ShutdownTimeout.String(),
+		"MAX_DECOMPRESSED_BODY_BYTES": c.MaxDecompressedBytes,
+
+		"API_BASE_PATH":           c.APIBasePath,
+		"API_BASE_PATH_PROBES":    c.BasePathProbes,
+		"API_ENVELOPE":            c.APIEnvelope,
+		"PRETTY_JSON":             c.PrettyJSON,
+		"ADMIN_ENDPOINTS_ENABLED": c.AdminEnabled,
+		"SSE_MAX_SUBSCRIBERS":     c.SSEMaxSubscribers,
+		"IDEMPOTENCY_TTL":         c.IdempotencyTTL.String(),
+		"LIST_MAX_UNPAGED":        c.ListMaxUnpaged,
+		"HEALTH_CHECK_DEEP":       c.HealthCheckDeep,
+
+		"STORAGE_BACKEND":      "memory",
+		"STORAGE_SHARDS":       c.StorageShards,
+		"HISTORY_MAX_VERSIONS": c.HistoryLimit,
+		"STORAGE_MAX_ITEMS":    c.StorageMaxItems,
+		"STORAGE_FULL_POLICY":  c.StorageFullPolicy,
+		"READ_CACHE_TTL":       c.ReadCacheTTL.String(),
+		"DESCRIPTION_MAX":      c.DescriptionMax,
+		"DESCRIPTION_OVERFLOW": c.DescriptionOverflow,
+		"ID_STRATEGY":          c.IDStrategy,
+		"TIME_FORMAT":          c.TimeFormat,
+		"METADATA_KEYS":        strings.Join(c.MetadataKeys, ","),
+		"TENANT_ADMIN_IDS":     strings.Join(c.TenantAdminIDs, ","),
+		"SNAPSHOT_PATH":        c.SnapshotPath,
+		"SNAPSHOT_INTERVAL":    c.SnapshotInterval.String(),
+	}
+}
+
+// redactEndpoints hides credentials embedded in endpoint URLs
+func redactEndpoints(endpoints string) string {
+	parts := strings.Split(endpoints, ",")
+	for i, endpoint := range parts {
+		endpoint = strings.TrimSpace(endpoint)
+		if u, err := url.Parse(endpoint); err == nil && u.User != nil {
+			u.User = url.User("REDACTED")
+			endpoint = u.String()
+		}
+		parts[i] = endpoint
+	}
+	return strings.Join(parts, ",")
+}
+
+// env reads typed variables through lookup, collecting parse errors
+type env struct {
+	lookup func(key string) (string, bool)
+	errs   []error
+}
+
+func (e *env) str(key, fallback string) string {
+	if value, exists := e.lookup(key); exists {
+		return value
+	}
+	return fallback
+}
+
+// list reads a comma-separated list, dropping blank entries. A variable that
+// is unset or holds no entries yields fallback.
+func (e *env) list(key string, fallback []string) []string {
+	var values []string
+	raw, _ := e.lookup(key)
+	for _, value := range strings.Split(raw, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	if len(values) == 0 {
+		return fallback
+	}
+	return values
+}
+
+func (e *env) integer(key string, fallback int) int {
+	value, exists := e.lookup(key)
+	if !exists {
+		return fallback
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		e.errs = append(e.errs, fmt.Errorf("%s=%q is not an integer", key, value))
+		return fallback
+	}
+	return i
+}
+
+func (e *env) duration(key string, fallback time.Duration) time.Duration {
+	value, exists := e.lookup(key)
+	if !exists {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		e.errs = append(e.errs, fmt.Errorf("%s=%q is not a duration such as 500ms or 10s", key, value))
+		return fallback
+	}
+	return d
+}
+
+func (e *env) float(key string, fallback float64) float64 {
+	value, exists := e.lookup(key)
+	if !exists {
+		return fallback
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		e.errs = append(e.errs, fmt.Errorf("%s=%q is not a number", key, value))
+		return fallback
+	}
+	return f
+}
+
+func (e *env) boolean(key string, fallback bool) bool {
+	value, exists := e.lookup(key)
+	if !exists {
+		return fallback
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		e.errs = append(e.errs, fmt.Errorf("%s=%q is not a boolean", key, value))
+		return fallback
+	}
+	return b
+}
@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+
+	"github.com/gin-gonic/gin"
+	"github.com/misua/eks-with-otel/demo-app/internal/middleware"
+	"github.com/misua/eks-with-otel/demo-app/internal/storage"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceFlusher pushes queued spans to the collector immediately
+type TraceFlusher interface {
+	ForceFlush(ctx context.Context) (int, error)
+}
+
+// Snapshotter saves the whole store to a file and restores it, implemented by
+// storage.MemoryStorage
+type Snapshotter interface {
+	SaveSnapshot(ctx context.Context, path string) (int, error)
+	LoadSnapshot(ctx context.Context, path string) (int, error)
+}
+
+// AdminHandler serves the operational endpoints under /api/v1/admin, which
+// are only routed when ADMIN_ENDPOINTS_ENABLED is set
+type AdminHandler struct {
+	storage storage.Storage
+	tracing TraceFlusher
+	logger  *logrus.Logger
+
+	// snapshotPath is where the snapshot endpoints save and restore from
+	snapshotPath string
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(storage storage.Storage, tracing TraceFlusher, logger *logrus.Logger, snapshotPath string) *AdminHandler {
+	return &AdminHandler{
+		storage:      storage,
+		tracing:      tracing,
+		logger:       logger,
+		snapshotPath: snapshotPath,
+	}
+}
+
+// StorageStats handles GET /api/v1/admin/storage
+func (h *AdminHandler) StorageStats(c *gin.Context) {
+	ctx, span := startSpan(c, "handler.admin_storage_stats")
+	defer span.End()
+
+	spanCtx := trace.SpanContextFromContext(ctx)
+	logFields := withTraceSampled(spanCtx, logrus.Fields{
+		"trace_id": spanCtx.TraceID().String(),
+		"span_id":  spanCtx.SpanID().String(),
+		"method":   "GET",
+		"endpoint": "/api/v1/admin/storage",
+	})
+
+	stats, err := h.storage.DebugStats(ctx)
+	if err != nil {
+		writeStorageError(c, h.logger, span, logFields, err, "Failed to collect storage stats")
+		return
+	}
+
+	span.SetAttributes(
+		attribute.Int("storage.total_items", stats.TotalItems),
+		attribute.String("response.status", "success"),
+	)
+
+	logFields["total_items"] = stats.TotalItems
+	h.logger.WithFields(logFields).Info("Storage stats retrieved")
+
+	respondOK(c, stats, nil)
+}
+
+// FlushTraces handles POST /api/v1/admin/flush-traces
+func (h *AdminHandler) FlushTraces(c *gin.Context) {
+	ctx, span := startSpan(c, "handler.admin_flush_traces")
+	defer span.End()
+
+	spanCtx := trace.SpanContextFromContext(ctx)
+	logFields := withTraceSampled(spanCtx, logrus.Fields{
+		"trace_id": spanCtx.TraceID().String(),
+		"span_id":  spanCtx.SpanID().String(),
+		"method":   "POST",
+		"endpoint": "/api/v1/admin/flush-traces",
+	})
+
+	// This request's own spans are still open and go out with the next batch
+	flushed, err := 0, middleware.ErrTracingDisabled
+	if h.tracing != nil {
+		flushed, err = h.tracing.ForceFlush(ctx)
+	}
+	if err != nil {
+		span.RecordError(err)
+		if errors.Is(err, middleware.ErrTracingDisabled) {
+			span.SetAttributes(attribute.String("error.type", "tracing_disabled"))
+
+			h.logger.WithFields(logFields).Warn("Trace flush requested while tracing is disabled")
+			writeJSON(c, http.StatusServiceUnavailable, gin.H{"error": "Tracing is disabled, no collector reachable"})
+			return
+		}
+
+		span.SetAttributes(attribute.String("error.type", "flush_error"))
+
+		h.logger.WithFields(logFields).WithError(err).Error("Failed to flush traces")
+		writeJSON(c, http.StatusInternalServerError, gin.H{"error": "Failed to flush traces"})
+		return
+	}
+
+	span.SetAttributes(
+		attribute.Int("traces.flushed_spans", flushed),
+		attribute.String("response.status", "success"),
+	)
+
+	logFields["flushed_spans"] = flushed
+	h.logger.WithFields(logFields).Info("Traces flushed")
+
+	respondOK(c, gin.H{"flushed_spans": flushed}, nil)
+}
+
+// SaveSnapshot handles POST /api/v1/admin/snapshot
+func (h *AdminHandler) SaveSnapshot(c *gin.Context) {
+	ctx, span := startSpan(c, "handler.admin_save_snapshot")
+	defer span.End()
+
+	spanCtx := trace.SpanContextFromContext(ctx)
+	logFields := withTraceSampled(spanCtx, logrus.Fields{
+		"trace_id": spanCtx.TraceID().String(),
+		"span_id":  spanCtx.SpanID().String(),
+		"method":   "POST",
+		"endpoint": "/api/v1/admin/snapshot",
+		"path":     h.snapshotPath,
+	})
+
+	snapshotter, ok := h.storage.(Snapshotter)
+	if !ok {
+		span.SetAttributes(attribute.String("error.type", "not_supported"))
+		writeJSON(c, http.StatusNotImplemented, gin.H{"error": "Storage backend does not support snapshots"})
+		return
+	}
+
+	saved, err := snapshotter.SaveSnapshot(ctx, h.snapshotPath)
+	if err != nil {
+		writeStorageError(c, h.logger, span, logFields, err, "Failed to save snapshot")
+		return
+	}
+
+	span.SetAttributes(
+		attribute.Int("snapshot.items", saved),
+		attribute.String("response.status", "success"),
+	)
+
+	logFields["items"] = saved
+	h.logger.WithFields(logFields).Info("Snapshot saved")
+
+	respondOK(c, gin.H{"items": saved, "path": h.snapshotPath}, nil)
+}
+
+// RestoreSnapshot handles POST /api/v1/admin/restore, replacing every stored
+// item with the snapshot's
+func (h *AdminHandler) RestoreSnapshot(c *gin.Context) {
+	ctx, span := startSpan(c, "handler.admin_restore_snapshot")
+	defer span.End()
+
+	spanCtx := trace.SpanContextFromContext(ctx)
+	logFields := withTraceSampled(spanCtx, logrus.Fields{
+		"trace_id": spanCtx.TraceID().String(),
+		"span_id":  spanCtx.SpanID().String(),
+		"method":   "POST",
+		"endpoint": "/api/v1/admin/restore",
+		"path":     h.snapshotPath,
+	})
+
+	snapshotter, ok := h.storage.(Snapshotter)
+	if !ok {
+		span.SetAttributes(attribute.String("error.type", "not_supported"))
+		writeJSON(c, http.StatusNotImplemented, gin.H{"error": "Storage backend does not support snapshots"})
+		return
+	}
+
+	restored, err := snapshotter.LoadSnapshot(ctx, h.snapshotPath)
+	if errors.Is(err, os.ErrNotExist) {
+		span.RecordError(err)
+		span.SetAttributes(attribute.String("error.type", "not_found"))
+
+		h.logger.WithFields(logFields).Warn("No snapshot to restore")
+		writeJSON(c, http.StatusNotFound, gin.H{"error": "No snapshot found"})
+		return
+	}
+	if err != nil {
+		writeStorageError(c, h.logger, span, logFields, err, "Failed to restore snapshot")
+		return
+	}
+
+	span.SetAttributes(
+		attribute.Int("snapshot.items", restored),
+		attribute.String("response.status", "success"),
+	)
+
+	logFields["items"] = restored
+	h.logger.WithFields(logFields).Info("Snapshot restored")
+
+	respondOK(c, gin.H{"items": restored, "path": h.snapshotPath}, nil)
+}
+
+// DebugConfig serves GET /debug/config with the effective configuration,
+// which is resolved once at startup and already redacted by the caller
+func DebugConfig(effective map[string]any) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, span := startSpan(c, "handler.debug_config")
+		defer span.End()
+
+		span.SetAttributes(attribute.Int("config.values", len(effective)))
+		respondOK(c, effective, nil)
+	}
+}
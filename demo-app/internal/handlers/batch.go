@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/misua/eks-with-otel/demo-app/internal/models"
+	"github.com/misua/eks-with-otel/demo-app/internal/storage"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxBatchSize caps the number of IDs a single batch request may touch
+const maxBatchSize = 1000
+
+// PatchItems handles PATCH /api/v1/items/batch, applying one partial update
+// to every listed item and reporting the outcome per ID
+func (h *ItemHandler) PatchItems(c *gin.Context) {
+	ctx, span := startSpan(c, "handler.patch_items")
+	defer span.End()
+
+	spanCtx := trace.SpanContextFromContext(ctx)
+	logFields := withTraceSampled(spanCtx, logrus.Fields{
+		"trace_id": spanCtx.TraceID().String(),
+		"span_id":  spanCtx.SpanID().String(),
+		"method":   "PATCH",
+		"endpoint": "/api/v1/items/batch",
+	})
+	tenant := resolveTenant(c, span, logFields)
+
+	var req struct {
+		IDs   []string         `json:"ids" binding:"required"`
+		Patch models.ItemPatch `json:"patch"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		span.RecordError(err)
+		span.SetAttributes(attribute.String("error.type", "validation_error"))
+
+		h.logger.WithFields(logFields).WithError(err).Error("Invalid request payload")
+		writeJSON(c, http.StatusBadRequest, gin.H{"error": "Invalid request payload"})
+		return
+	}
+
+	span.SetAttributes(attribute.Int("batch.size", len(req.IDs)))
+	logFields["batch_size"] = len(req.IDs)
+
+	if message := validatePatchBatch(req.IDs, &req.Patch); message != "" {
+		span.SetAttributes(attribute.String("error.type", "validation_error"))
+
+		h.logger.WithFields(logFields).Warn(message)
+		writeJSON(c, http.StatusBadRequest, gin.H{"error": message})
+		return
+	}
+
+	// Items of other tenants are reported as not found without being touched
+	ids := req.IDs
+	hidden := make(map[string]bool)
+	if tenant.scoped() {
+		ids = make([]string, 0, len(req.IDs))
+		for _, id := range req.IDs {
+			if item, err := h.storage.GetByID(ctx, id); err == nil && !tenant.canSee(item) {
+				hidden[id] = true
+				continue
+			}
+			ids = append(ids, id)
+		}
+	}
+
+	patched, err := h.storage.PatchBatch(ctx, ids, req.Patch)
+	if err != nil {
+		writeStorageError(c, h.logger, span, logFields, err, "Failed to patch items")
+		return
+	}
+
+	// PatchBatch answers each visible ID in order; hidden IDs keep their
+	// place in the request
+	results := make([]storage.BatchResult, 0, len(req.IDs))
+	updated, notFound := 0, 0
+	for _, id := range req.IDs {
+		result := storage.BatchResult{ID: id, Status: storage.BatchNotFound}
+		if !hidden[id] {
+			result, patched = patched[0], patched[1:]
+		}
+		if result.Status == storage.BatchUpdated {
+			updated++
+		} else {
+			notFound++
+		}
+		results = append(results, result)
+	}
+
+	span.SetAttributes(
+		attribute.Int("batch.updated", updated),
+		attribute.Int("batch.not_found", notFound),
+		attribute.String("response.status", "success"),
+	)
+
+	logFields["updated"] = updated
+	logFields["not_found"] = notFound
+	h.logger.WithFields(logFields).Info("Batch patch applied")
+
+	respondOK(c, results, gin.H{"updated": updated, "not_found": notFound})
+}
+
+// validatePatchBatch checks a batch request, normalizing the patched
+// description in place. It returns an error message, or "" when valid.
+func validatePatchBatch(ids []string, patch *models.ItemPatch) string {
+	if len(ids) == 0 {
+		return "ids must not be empty"
+	}
+	if len(ids) > maxBatchSize {
+		return "too many ids in one batch"
+	}
+	if patch.IsEmpty() {
+		return "patch must set at least one field"
+	}
+	if patch.Name != nil && strings.TrimSpace(*patch.Name) == "" {
+		return "name must not be empty"
+	}
+	if patch.Description != nil {
+		description, _, err := models.NormalizeDescription(*patch.Description)
+		if err != nil {
+			return err.Error()
+		}
+		patch.Description = &description
+	}
+	return ""
+}
@@ -0,0 +1,47 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestPatchItemsKeepsRequestOrder(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		// wantA and wantB are the statuses reported for the items of a and b
+		wantA, wantB string
+	}{
+		{"owner of b", map[string]string{"X-Tenant-ID": "b"}, "not_found", "updated"},
+		{"owner of a", map[string]string{"X-Tenant-ID": "a"}, "updated", "not_found"},
+		{"no tenant", nil, "updated", "updated"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newTestServer(t)
+			a1 := createItem(t, srv, "a", "a1")
+			b1 := createItem(t, srv, "b", "b1")
+			a2 := createItem(t, srv, "a", "a2")
+
+			ids := []string{a1, b1, "missing", a2, b1}
+			want := []string{tt.wantA, tt.wantB, "not_found", tt.wantA, tt.wantB}
+
+			status, body := do(t, srv, http.MethodPatch, "/api/v1/items/batch", tt.headers,
+				map[string]any{"ids": ids, "patch": map[string]any{"name": "patched"}})
+			if status != http.StatusOK {
+				t.Fatalf("status %d, want 200 (body %v)", status, body)
+			}
+
+			results, _ := body["items"].([]any)
+			if len(results) != len(ids) {
+				t.Fatalf("got %d results, want %d (body %v)", len(results), len(ids), body)
+			}
+			for i, r := range results {
+				result, _ := r.(map[string]any)
+				if result["id"] != ids[i] || result["status"] != want[i] {
+					t.Errorf("result %d = %v/%v, want %s/%s", i, result["id"], result["status"], ids[i], want[i])
+				}
+			}
+		})
+	}
+}
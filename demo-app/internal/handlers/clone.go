@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"maps"
+
+	"github.com/gin-gonic/gin"
+	"github.com/misua/eks-with-otel/demo-app/internal/models"
+	"github.com/misua/eks-with-otel/demo-app/internal/storage"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// CloneItem handles POST /api/v1/items/:id/clone, creating a fresh copy of
+// the item's name, description and metadata under a new ID. The copy starts
+// at version 1, unflagged and owned by the caller's tenant. Deletes are not
+// soft in this store, so a deleted source is reported as not found.
+func (h *ItemHandler) CloneItem(c *gin.Context) {
+	ctx, span := startSpan(c, "handler.clone_item")
+	defer span.End()
+
+	id := c.Param("id")
+	span.SetAttributes(attribute.String("clone.source_id", id))
+
+	spanCtx := trace.SpanContextFromContext(ctx)
+	logFields := withTraceSampled(spanCtx, logrus.Fields{
+		"trace_id": spanCtx.TraceID().String(),
+		"span_id":  spanCtx.SpanID().String(),
+		"method":   "POST",
+		"endpoint": "/api/v1/items/:id/clone",
+		"item_id":  id,
+	})
+	tenant := resolveTenant(c, span, logFields)
+
+	source, err := h.storage.GetByID(ctx, id)
+	if err == nil && !tenant.canSee(source) {
+		err = storage.ErrItemNotFound
+	}
+	recordLookup(ctx, "clone", err)
+	if err != nil {
+		writeStorageError(c, h.logger, span, logFields, err, "Failed to clone item")
+		return
+	}
+
+	clone := models.NewItem(source.Name, source.Description)
+	clone.Owner = tenant.ID
+	clone.Metadata = maps.Clone(source.Metadata)
+
+	created, err := h.storage.Create(ctx, clone)
+	if err != nil {
+		writeStorageError(c, h.logger, span, logFields, err, "Failed to clone item")
+		return
+	}
+
+	span.SetAttributes(
+		attribute.String("clone.new_id", created.ID),
+		attribute.String("response.status", "success"),
+	)
+
+	logFields["new_item_id"] = created.ID
+	h.logger.WithFields(logFields).Info("Item cloned successfully")
+
+	respondCreated(c, created, nil)
+}
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/misua/eks-with-otel/demo-app/internal/models"
+)
+
+// itemETag is the strong entity tag of an item: its version, quoted
+func itemETag(item *models.Item) string {
+	return `"` + strconv.Itoa(item.Version) + `"`
+}
+
+// parseIfMatch reads the If-Match header of a conditional write as the item
+// version it expects, given as an ETag ("3") or a bare version (3). No header
+// and * match any version and return 0. Only a single tag is accepted.
+func parseIfMatch(c *gin.Context) (int, error) {
+	value := strings.TrimSpace(c.GetHeader("If-Match"))
+	if value == "" || value == "*" {
+		return 0, nil
+	}
+	version, err := strconv.Atoi(strings.Trim(value, `"`))
+	if err != nil || version <= 0 {
+		return 0, errors.New(`If-Match must be a single item version, e.g. "3"`)
+	}
+	return version, nil
+}
@@ -0,0 +1,50 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestPatchItemIfMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		ifMatch string
+		want    int
+	}{
+		{"no header", "", http.StatusOK},
+		{"current version", `"1"`, http.StatusOK},
+		{"bare version", "1", http.StatusOK},
+		{"any version", "*", http.StatusOK},
+		{"stale version", `"5"`, http.StatusPreconditionFailed},
+		{"not a version", `"abc"`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newTestServer(t)
+			id := createItem(t, srv, "", "item")
+
+			headers := map[string]string{}
+			if tt.ifMatch != "" {
+				headers["If-Match"] = tt.ifMatch
+			}
+			status, body := do(t, srv, http.MethodPatch, "/api/v1/items/"+id, headers, map[string]string{"name": "patched"})
+			if status != tt.want {
+				t.Fatalf("status %d, want %d (body %v)", status, tt.want, body)
+			}
+
+			// Only a successful patch moves the item to version 2
+			wantETag := `"1"`
+			if tt.want == http.StatusOK {
+				wantETag = `"2"`
+			}
+			resp, err := srv.Client().Get(srv.URL + "/api/v1/items/" + id)
+			if err != nil {
+				t.Fatalf("get: %v", err)
+			}
+			resp.Body.Close()
+			if got := resp.Header.Get("ETag"); got != wantETag {
+				t.Errorf("ETag %q, want %q", got, wantETag)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// CountItems handles GET /api/v1/items/count. Scoped tenants get the number
+// of their own items.
+func (h *ItemHandler) CountItems(c *gin.Context) {
+	ctx, span := startSpan(c, "handler.count_items")
+	defer span.End()
+
+	spanCtx := trace.SpanContextFromContext(ctx)
+	logFields := withTraceSampled(spanCtx, logrus.Fields{
+		"trace_id": spanCtx.TraceID().String(),
+		"span_id":  spanCtx.SpanID().String(),
+		"method":   "GET",
+		"endpoint": "/api/v1/items/count",
+	})
+	tenant := resolveTenant(c, span, logFields)
+
+	var count int
+	var err error
+	if tenant.scoped() {
+		items, ownerErr := h.storage.GetAllForOwner(ctx, tenant.ID)
+		count, err = len(items), ownerErr
+	} else {
+		count, err = h.storage.Count(ctx)
+	}
+	if err != nil {
+		writeStorageError(c, h.logger, span, logFields, err, "Failed to count items")
+		return
+	}
+
+	span.SetAttributes(
+		attribute.Int("items.count", count),
+		attribute.String("response.status", "success"),
+	)
+
+	logFields["items_count"] = count
+	h.logger.WithFields(logFields).Debug("Items counted")
+
+	respondOK(c, gin.H{"count": count}, nil)
+}
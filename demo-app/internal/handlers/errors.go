@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/misua/eks-with-otel/demo-app/internal/storage"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Problem is the body of an error response
+type Problem struct {
+	// Type is recorded on the span as error.type
+	Type    string `json:"-"`
+	Message string `json:"error"`
+}
+
+// errorToStatus maps a storage error to its HTTP status and problem body.
+// Errors outside the storage error classes are internal: they map to 500
+// with no message, which writeStorageError fills in per endpoint.
+func errorToStatus(err error) (int, Problem) {
+	switch {
+	case storage.IsNotFound(err):
+		return http.StatusNotFound, Problem{Type: "not_found", Message: "Item not found"}
+	case errors.Is(err, storage.ErrVersionMismatch):
+		return http.StatusPreconditionFailed, Problem{Type: "version_mismatch", Message: "Item version does not match"}
+	case errors.Is(err, storage.ErrItemExists):
+		return http.StatusConflict, Problem{Type: "conflict", Message: "Item already exists"}
+	case storage.IsConflict(err):
+		return http.StatusConflict, Problem{Type: "conflict", Message: "Item was changed concurrently"}
+	case storage.IsFull(err):
+		return http.StatusInsufficientStorage, Problem{Type: "storage_full", Message: "Storage is full"}
+	case errors.Is(err, storage.ErrUnsupportedField):
+		return http.StatusBadRequest, Problem{Type: "validation_error", Message: "field must be owner, name or metadata.KEY"}
+	case errors.Is(err, storage.ErrInvalidSnapshot):
+		return http.StatusUnprocessableEntity, Problem{Type: "invalid_snapshot", Message: "Snapshot is invalid"}
+	case storage.IsInvalid(err):
+		return http.StatusBadRequest, Problem{Type: "validation_error", Message: "Invalid request"}
+	}
+	return http.StatusInternalServerError, Problem{Type: "storage_error"}
+}
+
+// writeStorageError answers a failed storage call with the status and body
+// errorToStatus picks, recording it on the span and in the log. failure is
+// the message for internal errors, e.g. "Failed to delete item".
+func writeStorageError(c *gin.Context, logger *logrus.Logger, span trace.Span, logFields logrus.Fields, err error, failure string) {
+	status, problem := errorToStatus(err)
+	span.SetAttributes(attribute.String("error.type", problem.Type))
+
+	switch {
+	case status == http.StatusNotFound:
+		// Missing items are an expected outcome, not a span error
+		span.SetAttributes(attribute.Bool("item.found", false))
+		logger.WithFields(logFields).Warn(problem.Message)
+	case status >= http.StatusInternalServerError:
+		span.RecordError(err)
+		problem.Message = failure
+		logger.WithFields(logFields).WithError(err).Error(failure)
+	default:
+		span.RecordError(err)
+		logger.WithFields(logFields).WithError(err).Warn(problem.Message)
+	}
+
+	writeJSON(c, status, problem)
+}
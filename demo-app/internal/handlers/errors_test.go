@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/misua/eks-with-otel/demo-app/internal/storage"
+)
+
+func TestErrorToStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantType   string
+	}{
+		{"item not found", storage.ErrItemNotFound, http.StatusNotFound, "not_found"},
+		{"wrapped item not found", fmt.Errorf("get 42: %w", storage.ErrItemNotFound), http.StatusNotFound, "not_found"},
+		{"not found class", storage.ErrNotFound, http.StatusNotFound, "not_found"},
+		{"version mismatch", fmt.Errorf("update 42: %w", storage.ErrVersionMismatch), http.StatusPreconditionFailed, "version_mismatch"},
+		{"item exists", fmt.Errorf("create 42: %w", storage.ErrItemExists), http.StatusConflict, "conflict"},
+		{"conflict class", storage.ErrConflict, http.StatusConflict, "conflict"},
+		{"storage full", fmt.Errorf("create 42: %w", storage.ErrStorageFull), http.StatusInsufficientStorage, "storage_full"},
+		{"unsupported field", storage.ErrUnsupportedField, http.StatusBadRequest, "validation_error"},
+		{"invalid snapshot", fmt.Errorf("load: %w", storage.ErrInvalidSnapshot), http.StatusUnprocessableEntity, "invalid_snapshot"},
+		{"invalid class", storage.ErrInvalid, http.StatusBadRequest, "validation_error"},
+		{"cancelled context", context.Canceled, http.StatusInternalServerError, "storage_error"},
+		{"unknown error", errors.New("disk on fire"), http.StatusInternalServerError, "storage_error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, problem := errorToStatus(tt.err)
+			if status != tt.wantStatus {
+				t.Errorf("status = %d, want %d", status, tt.wantStatus)
+			}
+			if problem.Type != tt.wantType {
+				t.Errorf("problem type = %q, want %q", problem.Type, tt.wantType)
+			}
+			// Internal errors get their message per endpoint from writeStorageError
+			if internal := problem.Type == "storage_error"; internal != (problem.Message == "") {
+				t.Errorf("problem message = %q for type %s", problem.Message, problem.Type)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/misua/eks-with-otel/demo-app/internal/storage"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// subscriberBuffer is how many events a slow subscriber may fall behind
+	// before further events are dropped for it
+	subscriberBuffer  = 64
+	keepaliveInterval = 15 * time.Second
+)
+
+var subscriberGauge, _ = otel.Meter("handlers").Int64UpDownCounter(
+	"sse.subscribers",
+	metric.WithDescription("Number of connected item event stream subscribers"),
+)
+
+// EventHandler streams storage changes to clients as Server-Sent Events
+type EventHandler struct {
+	logger         *logrus.Logger
+	maxSubscribers int
+
+	mu sync.Mutex
+	// subscribers maps each subscriber to the tenant scope it connected with
+	subscribers map[chan storage.StorageEvent]tenantScope
+}
+
+// NewEventHandler creates an event handler subscribed to the storage's mutations
+func NewEventHandler(store storage.Storage, logger *logrus.Logger, maxSubscribers int) *EventHandler {
+	h := &EventHandler{
+		logger:         logger,
+		maxSubscribers: maxSubscribers,
+		subscribers:    make(map[chan storage.StorageEvent]tenantScope),
+	}
+	store.AddObserver(h.publish)
+	return h
+}
+
+// publish fans an event out to the subscribers whose tenant may see the item,
+// without blocking the storage
+func (h *EventHandler) publish(event storage.StorageEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub, tenant := range h.subscribers {
+		if !tenant.canSee(&event.Item) {
+			continue
+		}
+		select {
+		case sub <- event:
+		default:
+			// Subscriber is too slow, drop the event rather than stall writers
+		}
+	}
+}
+
+// subscribe registers a new subscriber limited to the tenant's items, or
+// reports false when the limit is reached
+func (h *EventHandler) subscribe(ctx context.Context, tenant tenantScope) (chan storage.StorageEvent, int, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.subscribers) >= h.maxSubscribers {
+		return nil, len(h.subscribers), false
+	}
+
+	sub := make(chan storage.StorageEvent, subscriberBuffer)
+	h.subscribers[sub] = tenant
+	subscriberGauge.Add(ctx, 1)
+	return sub, len(h.subscribers), true
+}
+
+// unsubscribe removes a subscriber
+func (h *EventHandler) unsubscribe(ctx context.Context, sub chan storage.StorageEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.subscribers, sub)
+	subscriberGauge.Add(ctx, -1)
+}
+
+// StreamItemEvents handles GET /api/v1/items/events. Scoped tenants only
+// receive events for their own items.
+func (h *EventHandler) StreamItemEvents(c *gin.Context) {
+	ctx, span := startSpan(c, "handler.stream_item_events")
+	defer span.End()
+
+	spanCtx := trace.SpanContextFromContext(ctx)
+	logFields := withTraceSampled(spanCtx, logrus.Fields{
+		"trace_id": spanCtx.TraceID().String(),
+		"span_id":  spanCtx.SpanID().String(),
+		"method":   "GET",
+		"endpoint": "/api/v1/items/events",
+	})
+	tenant := resolveTenant(c, span, logFields)
+
+	sub, subscribers, ok := h.subscribe(ctx, tenant)
+	span.SetAttributes(attribute.Int("sse.subscribers", subscribers))
+	if !ok {
+		span.SetAttributes(attribute.String("error.type", "too_many_subscribers"))
+
+		h.logger.WithFields(logFields).Warn("Event stream subscriber limit reached")
+		writeJSON(c, http.StatusServiceUnavailable, gin.H{"error": "Too many event stream subscribers"})
+		return
+	}
+	defer h.unsubscribe(ctx, sub)
+
+	h.logger.WithFields(logFields).Info("Event stream subscriber connected")
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+
+	keepalive := time.NewTicker(keepaliveInterval)
+	defer keepalive.Stop()
+
+	sent := 0
+	c.Stream(func(_ io.Writer) bool {
+		select {
+		case <-ctx.Done():
+			return false
+		case event := <-sub:
+			c.SSEvent(string(event.Operation), gin.H{
+				"operation": event.Operation,
+				"item":      event.Item,
+				"timestamp": event.Timestamp,
+			})
+			sent++
+			return true
+		case <-keepalive.C:
+			c.SSEvent("ping", gin.H{"timestamp": time.Now()})
+			return true
+		}
+	})
+
+	span.SetAttributes(attribute.Int("sse.events_sent", sent))
+	logFields["events_sent"] = sent
+	h.logger.WithFields(logFields).Info("Event stream subscriber disconnected")
+}
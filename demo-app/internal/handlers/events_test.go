@@ -0,0 +1,76 @@
+package handlers_test
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStreamItemEventsTenantScope(t *testing.T) {
+	srv := newTestServer(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/api/v1/items/events", nil)
+	req.Header.Set("X-Tenant-ID", "b")
+
+	lines := make(chan string, 64)
+	go func() {
+		defer close(lines)
+		resp, err := srv.Client().Do(req)
+		if err != nil {
+			return
+		}
+		defer resp.Body.Close()
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			if line := scanner.Text(); strings.HasPrefix(line, "data:") {
+				lines <- line
+			}
+		}
+	}()
+
+	// The stream sends nothing before the first event, so keep creating
+	// until the subscriber has seen one of its own
+	next := func() (string, bool) {
+		select {
+		case line, ok := <-lines:
+			return line, ok
+		case <-time.After(50 * time.Millisecond):
+			return "", true
+		}
+	}
+	for subscribed := false; !subscribed; {
+		if ctx.Err() != nil {
+			t.Fatal("stream never delivered an event")
+		}
+		createItem(t, srv, "b", "warm up")
+		line, ok := next()
+		if !ok {
+			t.Fatal("stream closed")
+		}
+		subscribed = line != ""
+	}
+
+	hidden := createItem(t, srv, "a", "owned by a")
+	visible := createItem(t, srv, "b", "owned by b")
+	for {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				t.Fatal("stream closed before the visible event")
+			}
+			if strings.Contains(line, hidden) {
+				t.Fatalf("tenant b received an event for tenant a's item: %s", line)
+			}
+			if strings.Contains(line, visible) {
+				return
+			}
+		case <-ctx.Done():
+			t.Fatal("visible event never arrived")
+		}
+	}
+}
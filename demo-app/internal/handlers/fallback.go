@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// NotFound returns a JSON 404 for requests that match no route, so JSON-only
+// clients never see gin's plain-text default
+func NotFound(logger *logrus.Logger) gin.HandlerFunc {
+	return fallbackHandler(logger, "handler.not_found", http.StatusNotFound, "Route not found")
+}
+
+// MethodNotAllowed returns a JSON 405 for known routes called with an
+// unsupported method. Gin sets the Allow header before this handler runs.
+func MethodNotAllowed(logger *logrus.Logger) gin.HandlerFunc {
+	return fallbackHandler(logger, "handler.method_not_allowed", http.StatusMethodNotAllowed, "Method not allowed")
+}
+
+func fallbackHandler(logger *logrus.Logger, spanName string, status int, message string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, span := startSpan(c, spanName)
+		defer span.End()
+
+		spanCtx := trace.SpanContextFromContext(ctx)
+		logFields := withTraceSampled(spanCtx, logrus.Fields{
+			"trace_id": spanCtx.TraceID().String(),
+			"span_id":  spanCtx.SpanID().String(),
+			"method":   c.Request.Method,
+			"path":     c.Request.URL.Path,
+		})
+
+		span.SetAttributes(
+			attribute.String("http.path", c.Request.URL.Path),
+			attribute.Int("response.status_code", status),
+		)
+
+		body := gin.H{
+			"error":    message,
+			"path":     c.Request.URL.Path,
+			"trace_id": spanCtx.TraceID().String(),
+		}
+		if allow := c.Writer.Header().Get("Allow"); allow != "" {
+			span.SetAttributes(attribute.String("http.allow", allow))
+			logFields["allow"] = allow
+			body["allowed_methods"] = allow
+		}
+
+		logger.WithFields(logFields).Warn(message)
+		writeJSON(c, status, body)
+	}
+}
@@ -0,0 +1,68 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestFallbackHandlers(t *testing.T) {
+	recordSpans(t)
+	srv := newTestServer(t)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		wantStatus int
+		wantError  string
+		wantAllow  []string
+	}{
+		{"unknown route", http.MethodGet, "/no/such/route", http.StatusNotFound, "Route not found", nil},
+		{"unknown API route", http.MethodPost, "/api/v1/widgets", http.StatusNotFound, "Route not found", nil},
+		{"wrong method on health", http.MethodPut, "/health", http.StatusMethodNotAllowed, "Method not allowed", []string{"GET"}},
+		{"wrong method on item", http.MethodPost, "/api/v1/items/123", http.StatusMethodNotAllowed, "Method not allowed", []string{"GET", "PUT", "DELETE"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(tt.method, srv.URL+tt.path, nil)
+			resp, err := srv.Client().Do(req)
+			if err != nil {
+				t.Fatalf("request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("status %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+				t.Errorf("Content-Type = %q, want JSON", ct)
+			}
+			allow := resp.Header.Get("Allow")
+			for _, method := range tt.wantAllow {
+				if !strings.Contains(allow, method) {
+					t.Errorf("Allow = %q, missing %s", allow, method)
+				}
+			}
+			if tt.wantAllow == nil && allow != "" {
+				t.Errorf("Allow = %q on a 404", allow)
+			}
+
+			var body map[string]any
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			if body["error"] != tt.wantError {
+				t.Errorf("error = %v, want %q", body["error"], tt.wantError)
+			}
+			if body["path"] != tt.path {
+				t.Errorf("path = %v, want %q", body["path"], tt.path)
+			}
+			traceID, _ := body["trace_id"].(string)
+			if len(traceID) != 32 || traceID == strings.Repeat("0", 32) {
+				t.Errorf("trace_id = %q, want a valid trace ID", traceID)
+			}
+		})
+	}
+}
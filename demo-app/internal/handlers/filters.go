@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/misua/eks-with-otel/demo-app/internal/models"
+	"github.com/misua/eks-with-otel/demo-app/internal/storage"
+)
+
+// metadataParamPrefix marks list query parameters that filter on item
+// metadata, e.g. ?metadata.color=red
+const metadataParamPrefix = "metadata."
+
+// parseTimeRange reads the created_/updated_ after/before RFC3339 query
+// parameters. Missing parameters leave that bound open.
+func parseTimeRange(c *gin.Context) (storage.TimeRange, error) {
+	var r storage.TimeRange
+	params := []struct {
+		name  string
+		bound *time.Time
+	}{
+		{"created_after", &r.CreatedAfter},
+		{"created_before", &r.CreatedBefore},
+		{"updated_after", &r.UpdatedAfter},
+		{"updated_before", &r.UpdatedBefore},
+	}
+
+	for _, p := range params {
+		value := c.Query(p.name)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return r, fmt.Errorf("invalid %s: expected an RFC3339 timestamp", p.name)
+		}
+		*p.bound = t
+	}
+	return r, nil
+}
+
+// parseMetadataFilter collects the metadata.<key>=<value> query parameters
+func parseMetadataFilter(c *gin.Context) map[string]string {
+	var filter map[string]string
+	for param, values := range c.Request.URL.Query() {
+		key, ok := strings.CutPrefix(param, metadataParamPrefix)
+		if !ok || key == "" || len(values) == 0 {
+			continue
+		}
+		if filter == nil {
+			filter = make(map[string]string)
+		}
+		filter[key] = values[0]
+	}
+	return filter
+}
+
+// parseFlaggedFilter reads ?flagged=true|false; nil means no filter
+func parseFlaggedFilter(c *gin.Context) (*bool, error) {
+	value := c.Query("flagged")
+	if value == "" {
+		return nil, nil
+	}
+	flagged, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, fmt.Errorf("flagged must be true or false")
+	}
+	return &flagged, nil
+}
+
+// filterByFlag keeps the items whose flag equals flagged
+func filterByFlag(items []*models.Item, flagged bool) []*models.Item {
+	matched := make([]*models.Item, 0, len(items))
+	for _, item := range items {
+		if item.Flagged == flagged {
+			matched = append(matched, item)
+		}
+	}
+	return matched
+}
+
+// filterByMetadata keeps the items whose metadata matches every filter entry
+func filterByMetadata(items []*models.Item, filter map[string]string) []*models.Item {
+	matched := make([]*models.Item, 0, len(items))
+	for _, item := range items {
+		if item.MetadataMatches(filter) {
+			matched = append(matched, item)
+		}
+	}
+	return matched
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/misua/eks-with-otel/demo-app/internal/storage"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// FlagItem handles POST /api/v1/items/:id/flag
+func (h *ItemHandler) FlagItem(c *gin.Context) {
+	h.setFlag(c, true)
+}
+
+// UnflagItem handles POST /api/v1/items/:id/unflag
+func (h *ItemHandler) UnflagItem(c *gin.Context) {
+	h.setFlag(c, false)
+}
+
+func (h *ItemHandler) setFlag(c *gin.Context, flagged bool) {
+	endpoint := "/api/v1/items/:id/unflag"
+	if flagged {
+		endpoint = "/api/v1/items/:id/flag"
+	}
+
+	ctx, span := startSpan(c, "handler.set_item_flag")
+	defer span.End()
+
+	id := c.Param("id")
+	span.SetAttributes(
+		attribute.String("item.id", id),
+		attribute.Bool("item.flagged", flagged),
+	)
+
+	spanCtx := trace.SpanContextFromContext(ctx)
+	logFields := withTraceSampled(spanCtx, logrus.Fields{
+		"trace_id": spanCtx.TraceID().String(),
+		"span_id":  spanCtx.SpanID().String(),
+		"method":   "POST",
+		"endpoint": endpoint,
+		"item_id":  id,
+		"flagged":  flagged,
+	})
+	tenant := resolveTenant(c, span, logFields)
+
+	// Scoped tenants may not flag items they cannot see
+	if tenant.scoped() {
+		existing, err := h.storage.GetByID(ctx, id)
+		if err == nil && !tenant.canSee(existing) {
+			err = storage.ErrItemNotFound
+		}
+		if err != nil {
+			writeStorageError(c, h.logger, span, logFields, err, "Failed to flag item")
+			return
+		}
+	}
+
+	item, err := h.storage.SetFlag(ctx, id, flagged)
+	if err != nil {
+		writeStorageError(c, h.logger, span, logFields, err, "Failed to flag item")
+		return
+	}
+
+	span.SetAttributes(
+		attribute.Bool("item.found", true),
+		attribute.String("response.status", "success"),
+	)
+
+	h.logger.WithFields(logFields).Info("Item flag set")
+
+	respondOK(c, item, nil)
+}
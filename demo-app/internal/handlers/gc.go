@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"runtime"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// heapStats is the part of runtime.MemStats reported around a forced GC
+type heapStats struct {
+	HeapAllocBytes uint64 `json:"heap_alloc_bytes"`
+	HeapInuseBytes uint64 `json:"heap_inuse_bytes"`
+	HeapObjects    uint64 `json:"heap_objects"`
+	NumGC          uint32 `json:"num_gc"`
+}
+
+func readHeapStats() heapStats {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	return heapStats{
+		HeapAllocBytes: m.HeapAlloc,
+		HeapInuseBytes: m.HeapInuse,
+		HeapObjects:    m.HeapObjects,
+		NumGC:          m.NumGC,
+	}
+}
+
+// ForceGC handles POST /api/v1/admin/gc, running a full collection and
+// reporting the heap before and after it, e.g. to show memory freed by
+// deleted or evicted items. The collection stops the world, so it is meant
+// for demos, not for production tuning.
+func (h *AdminHandler) ForceGC(c *gin.Context) {
+	ctx, span := startSpan(c, "handler.admin_gc")
+	defer span.End()
+
+	spanCtx := trace.SpanContextFromContext(ctx)
+	logFields := withTraceSampled(spanCtx, logrus.Fields{
+		"trace_id": spanCtx.TraceID().String(),
+		"span_id":  spanCtx.SpanID().String(),
+		"method":   "POST",
+		"endpoint": "/api/v1/admin/gc",
+	})
+
+	before := readHeapStats()
+	start := time.Now()
+	runtime.GC()
+	duration := time.Since(start)
+	after := readHeapStats()
+
+	// Signed, the heap can grow while other requests allocate during the GC
+	freed := int64(before.HeapAllocBytes) - int64(after.HeapAllocBytes)
+	durationMs := float64(duration) / float64(time.Millisecond)
+	span.SetAttributes(
+		attribute.Int64("gc.heap_before_bytes", int64(before.HeapAllocBytes)),
+		attribute.Int64("gc.heap_after_bytes", int64(after.HeapAllocBytes)),
+		attribute.Int64("gc.freed_bytes", freed),
+		attribute.Float64("gc.duration_ms", durationMs),
+		attribute.String("response.status", "success"),
+	)
+
+	logFields["heap_before_bytes"] = before.HeapAllocBytes
+	logFields["heap_after_bytes"] = after.HeapAllocBytes
+	logFields["freed_bytes"] = freed
+	logFields["duration_ms"] = durationMs
+	h.logger.WithFields(logFields).Info("Forced garbage collection")
+
+	respondOK(c, gin.H{
+		"before":      before,
+		"after":       after,
+		"freed_bytes": freed,
+		"duration_ms": durationMs,
+	}, nil)
+}
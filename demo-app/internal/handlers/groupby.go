@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// GroupItems handles GET /api/v1/items/group-by?field=owner, returning the
+// number of items per distinct value of field (owner, name or metadata.<key>)
+func (h *ItemHandler) GroupItems(c *gin.Context) {
+	ctx, span := startSpan(c, "handler.group_items")
+	defer span.End()
+
+	field := c.Query("field")
+	span.SetAttributes(attribute.String("groupby.field", field))
+
+	spanCtx := trace.SpanContextFromContext(ctx)
+	logFields := withTraceSampled(spanCtx, logrus.Fields{
+		"trace_id": spanCtx.TraceID().String(),
+		"span_id":  spanCtx.SpanID().String(),
+		"method":   "GET",
+		"endpoint": "/api/v1/items/group-by",
+		"field":    field,
+	})
+	tenant := resolveTenant(c, span, logFields)
+
+	owner := ""
+	if tenant.scoped() {
+		owner = tenant.ID
+	}
+
+	groups, err := h.storage.GroupBy(ctx, field, owner)
+	if err != nil {
+		writeStorageError(c, h.logger, span, logFields, err, "Failed to group items")
+		return
+	}
+
+	span.SetAttributes(
+		attribute.Int("groupby.groups", len(groups)),
+		attribute.String("response.status", "success"),
+	)
+
+	logFields["groups"] = len(groups)
+	h.logger.WithFields(logFields).Info("Items grouped successfully")
+
+	respondOK(c, gin.H{"field": field, "groups": groups}, gin.H{"count": len(groups)})
+}
@@ -0,0 +1,107 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/misua/eks-with-otel/demo-app/internal/server"
+	"github.com/misua/eks-with-otel/demo-app/internal/storage"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// newTestServer serves the full router on top of a fresh memory store
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	return newTestServerWithStore(t, storage.NewMemoryStorage())
+}
+
+// newTestServerWithStore serves the full router on top of store
+func newTestServerWithStore(t *testing.T, store storage.Storage) *httptest.Server {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	srv := httptest.NewServer(server.NewRouter(store, logger, server.RouterOptions{}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// do sends a request with an optional JSON body and returns the status and
+// the decoded JSON response, nil when the body is not a JSON object
+func do(t *testing.T, srv *httptest.Server, method, path string, headers map[string]string, body any) (int, map[string]any) {
+	t.Helper()
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("marshal body: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, srv.URL+path, reader)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	var decoded map[string]any
+	json.NewDecoder(resp.Body).Decode(&decoded)
+	return resp.StatusCode, decoded
+}
+
+// createItem creates an item, as tenant unless it is empty, and returns its ID
+func createItem(t *testing.T, srv *httptest.Server, tenant, name string) string {
+	t.Helper()
+	headers := map[string]string{}
+	if tenant != "" {
+		headers["X-Tenant-ID"] = tenant
+	}
+	status, body := do(t, srv, http.MethodPost, "/api/v1/items", headers, map[string]string{"name": name, "description": "test item"})
+	if status != http.StatusCreated {
+		t.Fatalf("create %q: status %d, body %v", name, status, body)
+	}
+	id, _ := body["id"].(string)
+	if id == "" {
+		t.Fatalf("create %q: no id in %v", name, body)
+	}
+	return id
+}
+
+var (
+	spanRecorder     *tracetest.SpanRecorder
+	spanRecorderOnce sync.Once
+)
+
+// recordSpans installs a tracer provider that keeps every ended span, so
+// handlers produce real trace IDs. The handlers' tracer binds to the first
+// provider installed, so all tests share one recorder and must pick out the
+// spans of their own requests.
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	spanRecorderOnce.Do(func() {
+		spanRecorder = tracetest.NewSpanRecorder()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder)))
+	})
+	return spanRecorder
+}
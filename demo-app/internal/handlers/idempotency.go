@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"sync"
+	"time"
+
+	"github.com/misua/eks-with-otel/demo-app/internal/models"
+)
+
+const idempotencyHeader = "Idempotency-Key"
+
+// idempotencyEntry remembers the outcome of a create for a given key. A nil
+// item means the original request is still in flight.
+type idempotencyEntry struct {
+	item    *models.Item
+	expires time.Time
+}
+
+// idempotencyStore is a short-lived map of Idempotency-Key to created item so
+// retried creates return the original response instead of a duplicate
+type idempotencyStore struct {
+	ttl time.Duration
+
+	mu        sync.Mutex
+	entries   map[string]idempotencyEntry
+	lastSweep time.Time
+}
+
+func newIdempotencyStore(ttl time.Duration) *idempotencyStore {
+	return &idempotencyStore{
+		ttl:       ttl,
+		entries:   make(map[string]idempotencyEntry),
+		lastSweep: time.Now(),
+	}
+}
+
+// begin looks up a key. It returns the previously created item on a hit, or
+// reserves the key and returns inFlight=false on a miss. inFlight is true when
+// another request with the same key has not finished yet.
+func (s *idempotencyStore) begin(key string) (item *models.Item, inFlight bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+
+	if entry, ok := s.entries[key]; ok && now.Before(entry.expires) {
+		if entry.item == nil {
+			return nil, true
+		}
+		return entry.item, false
+	}
+
+	s.entries[key] = idempotencyEntry{expires: now.Add(s.ttl)}
+	return nil, false
+}
+
+// complete records the created item for a reserved key
+func (s *idempotencyStore) complete(key string, item *models.Item) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot := *item
+	s.entries[key] = idempotencyEntry{item: &snapshot, expires: time.Now().Add(s.ttl)}
+}
+
+// release drops a reservation after a failed create so the client can retry
+func (s *idempotencyStore) release(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+}
+
+// sweep drops expired keys, at most once per TTL. Callers must hold s.mu.
+func (s *idempotencyStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < s.ttl {
+		return
+	}
+	for key, entry := range s.entries {
+		if !now.Before(entry.expires) {
+			delete(s.entries, key)
+		}
+	}
+	s.lastSweep = now
+}
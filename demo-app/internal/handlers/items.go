@@ -1,12 +1,19 @@
 package handlers
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 	"github.com/misua/eks-with-otel/demo-app/internal/models"
 	"github.com/misua/eks-with-otel/demo-app/internal/storage"
+	"github.com/misua/eks-with-otel/demo-app/internal/telemetry"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
@@ -14,61 +21,127 @@ import (
 
 var tracer = otel.Tracer("handlers")
 
+// ItemHandlerOptions configures optional ItemHandler behavior
+type ItemHandlerOptions struct {
+	// IdempotencyTTL is how long an Idempotency-Key is remembered on create
+	IdempotencyTTL time.Duration
+	// ListMaxUnpaged caps GET /api/v1/items without limit or offset; 0 lists
+	// everything
+	ListMaxUnpaged int
+	// ShallowHealth makes GET /health skip dependency probes and the item
+	// count unless ?deep=true is passed
+	ShallowHealth bool
+}
+
 // ItemHandler handles HTTP requests for items
 type ItemHandler struct {
-	storage *storage.MemoryStorage
-	logger  *logrus.Logger
+	storage     storage.Storage
+	logger      *logrus.Logger
+	idempotency *idempotencyStore
+	maxUnpaged  int
+	deepHealth  bool
 }
 
 // NewItemHandler creates a new item handler
-func NewItemHandler(storage *storage.MemoryStorage, logger *logrus.Logger) *ItemHandler {
+func NewItemHandler(storage storage.Storage, logger *logrus.Logger, opts ItemHandlerOptions) *ItemHandler {
 	return &ItemHandler{
-		storage: storage,
-		logger:  logger,
+		storage:     storage,
+		logger:      logger,
+		idempotency: newIdempotencyStore(opts.IdempotencyTTL),
+		maxUnpaged:  opts.ListMaxUnpaged,
+		deepHealth:  !opts.ShallowHealth,
 	}
 }
 
 // CreateItem handles POST /api/v1/items
 func (h *ItemHandler) CreateItem(c *gin.Context) {
-	ctx, span := tracer.Start(c.Request.Context(), "handler.create_item")
+	ctx, span := startSpan(c, "handler.create_item")
 	defer span.End()
 
 	// Extract trace information for logging
 	spanCtx := trace.SpanContextFromContext(ctx)
-	logFields := logrus.Fields{
+	logFields := withTraceSampled(spanCtx, logrus.Fields{
 		"trace_id": spanCtx.TraceID().String(),
 		"span_id":  spanCtx.SpanID().String(),
 		"method":   "POST",
 		"endpoint": "/api/v1/items",
-	}
+	})
+	tenant := resolveTenant(c, span, logFields)
 
 	var req struct {
-		Name        string `json:"name" binding:"required"`
-		Description string `json:"description"`
+		Name        string         `json:"name" binding:"required"`
+		Description string         `json:"description"`
+		Metadata    map[string]any `json:"metadata"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
 		span.RecordError(err)
 		span.SetAttributes(attribute.String("error.type", "validation_error"))
+		recordBindError(ctx, err)
 		
 		h.logger.WithFields(logFields).WithError(err).Error("Invalid request payload")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload"})
+		writeJSON(c, http.StatusBadRequest, gin.H{"error": "Invalid request payload"})
 		return
 	}
 
-	span.SetAttributes(
+	telemetry.SetDetail(span,
 		attribute.String("item.name", req.Name),
 		attribute.String("item.description", req.Description),
 	)
 
 	item := models.NewItem(req.Name, req.Description)
+	item.Owner = tenant.ID
+	item.Metadata = req.Metadata
+	if fieldErrs := item.Validate(); len(fieldErrs) > 0 {
+		span.SetAttributes(
+			attribute.String("error.type", "validation_error"),
+			attribute.String("validation.field", fieldErrs[0].Field),
+		)
+		recordFieldErrors(ctx, fieldErrs)
+
+		h.logger.WithFields(logFields).WithField("field_errors", fieldErrs).Error("Invalid request payload")
+		writeJSON(c, http.StatusBadRequest, gin.H{"error": fieldErrs[0].Message})
+		return
+	}
+	truncated, _ := item.Normalize()
+	span.SetAttributes(attribute.Bool("description.truncated", truncated))
+
+	// Keys are scoped per tenant so tenants cannot replay each other's creates
+	idempotencyKey := c.GetHeader(idempotencyHeader)
+	if idempotencyKey != "" {
+		scopedKey := tenant.ID + "/" + idempotencyKey
+		previous, inFlight := h.idempotency.begin(scopedKey)
+		span.SetAttributes(attribute.Bool("idempotency.hit", previous != nil))
+		logFields["idempotency_key"] = idempotencyKey
+
+		if inFlight {
+			span.SetAttributes(attribute.String("error.type", "idempotency_in_flight"))
+
+			h.logger.WithFields(logFields).Warn("Create with the same Idempotency-Key already in progress")
+			writeJSON(c, http.StatusConflict, gin.H{"error": "A request with this Idempotency-Key is already in progress"})
+			return
+		}
+		if previous != nil {
+			span.SetAttributes(
+				attribute.String("item.id", previous.ID),
+				attribute.String("response.status", "success"),
+			)
+
+			logFields["item_id"] = previous.ID
+			h.logger.WithFields(logFields).Info("Replayed idempotent create")
+			respondCreated(c, previous, nil)
+			return
+		}
+		idempotencyKey = scopedKey
+	}
+
 	createdItem, err := h.storage.Create(ctx, item)
 	if err != nil {
-		span.RecordError(err)
-		span.SetAttributes(attribute.String("error.type", "storage_error"))
-		
-		h.logger.WithFields(logFields).WithError(err).Error("Failed to create item")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create item"})
+		if idempotencyKey != "" {
+			h.idempotency.release(idempotencyKey)
+		}
+		logFields["item_id"] = item.ID
+		writeStorageError(c, h.logger, span, logFields, err, "Failed to create item")
 		return
 	}
 
@@ -77,201 +150,478 @@ func (h *ItemHandler) CreateItem(c *gin.Context) {
 		attribute.String("response.status", "success"),
 	)
 
+	if idempotencyKey != "" {
+		h.idempotency.complete(idempotencyKey, createdItem)
+	}
+
 	logFields["item_id"] = createdItem.ID
 	logFields["item_name"] = createdItem.Name
 	h.logger.WithFields(logFields).Info("Item created successfully")
 
-	c.JSON(http.StatusCreated, createdItem)
+	respondCreated(c, createdItem, nil)
+}
+
+// ValidateItem handles POST /api/v1/items/validate. It applies the same checks
+// as CreateItem but never touches storage.
+func (h *ItemHandler) ValidateItem(c *gin.Context) {
+	ctx, span := startSpan(c, "handler.validate_item")
+	defer span.End()
+
+	spanCtx := trace.SpanContextFromContext(ctx)
+	logFields := withTraceSampled(spanCtx, logrus.Fields{
+		"trace_id": spanCtx.TraceID().String(),
+		"span_id":  spanCtx.SpanID().String(),
+		"method":   "POST",
+		"endpoint": "/api/v1/items/validate",
+	})
+
+	// Name is checked by Validate so a missing name is reported as a field error
+	var req struct {
+		Name        string         `json:"name"`
+		Description string         `json:"description"`
+		Metadata    map[string]any `json:"metadata"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		span.RecordError(err)
+		span.SetAttributes(attribute.String("error.type", "invalid_json"))
+
+		h.logger.WithFields(logFields).WithError(err).Warn("Invalid request payload")
+		writeJSON(c, http.StatusBadRequest, gin.H{"error": "Invalid request payload"})
+		return
+	}
+
+	telemetry.SetDetail(span,
+		attribute.String("item.name", req.Name),
+		attribute.String("item.description", req.Description),
+	)
+
+	item := models.Item{Name: req.Name, Description: req.Description, Metadata: req.Metadata}
+	fieldErrs := item.Validate()
+	span.SetAttributes(
+		attribute.Bool("validation.valid", len(fieldErrs) == 0),
+		attribute.Int("validation.errors", len(fieldErrs)),
+	)
+	if len(fieldErrs) > 0 {
+		h.logger.WithFields(logFields).WithField("field_errors", fieldErrs).Info("Item payload rejected by validation")
+		writeJSON(c, http.StatusUnprocessableEntity, gin.H{"valid": false, "errors": fieldErrs})
+		return
+	}
+
+	h.logger.WithFields(logFields).Info("Item payload is valid")
+	writeJSON(c, http.StatusOK, gin.H{"valid": true})
 }
 
 // GetItems handles GET /api/v1/items
 func (h *ItemHandler) GetItems(c *gin.Context) {
-	ctx, span := tracer.Start(c.Request.Context(), "handler.get_items")
+	ctx, span := startSpan(c, "handler.get_items")
 	defer span.End()
 
 	spanCtx := trace.SpanContextFromContext(ctx)
-	logFields := logrus.Fields{
+	logFields := withTraceSampled(spanCtx, logrus.Fields{
 		"trace_id": spanCtx.TraceID().String(),
 		"span_id":  spanCtx.SpanID().String(),
 		"method":   "GET",
 		"endpoint": "/api/v1/items",
-	}
+	})
+	tenant := resolveTenant(c, span, logFields)
 
-	items, err := h.storage.GetAll(ctx)
+	metadataFilter := parseMetadataFilter(c)
+	timeRange, err := parseTimeRange(c)
+	var flagFilter *bool
+	if err == nil {
+		flagFilter, err = parseFlaggedFilter(c)
+	}
+	var page listPage
+	if err == nil {
+		page, err = parseListPage(c)
+	}
+	var fields []string
+	if err == nil {
+		fields, err = parseFields(c)
+	}
 	if err != nil {
 		span.RecordError(err)
-		span.SetAttributes(attribute.String("error.type", "storage_error"))
-		
-		h.logger.WithFields(logFields).WithError(err).Error("Failed to retrieve items")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve items"})
+		span.SetAttributes(attribute.String("error.type", "validation_error"))
+
+		h.logger.WithFields(logFields).WithError(err).Warn("Invalid list filter")
+		writeJSON(c, http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	var items []*models.Item
+	switch {
+	case !timeRange.IsZero():
+		items, err = h.storage.FilterByTimeRange(ctx, timeRange)
+		items = tenant.filter(items)
+	case tenant.scoped():
+		items, err = h.storage.GetAllForOwner(ctx, tenant.ID)
+	default:
+		items, err = h.storage.GetAll(ctx)
+	}
+	if err != nil {
+		writeStorageError(c, h.logger, span, logFields, err, "Failed to retrieve items")
+		return
+	}
+	if len(metadataFilter) > 0 {
+		items = filterByMetadata(items, metadataFilter)
+		span.SetAttributes(attribute.Int("filter.metadata_keys", len(metadataFilter)))
+	}
+	if flagFilter != nil {
+		items = filterByFlag(items, *flagFilter)
+		span.SetAttributes(attribute.Bool("filter.flagged", *flagFilter))
+	}
+
+	// Storage lists oldest first, so pages and the truncated head are stable.
+	// Streams are meant for full exports and are never capped
+	streamed := wantsNDJSON(c)
+	total := len(items)
+	truncated := false
+	switch {
+	case page.paged():
+		items = applyPage(items, page)
+		span.SetAttributes(
+			attribute.Int("page.limit", page.Limit),
+			attribute.Int("page.offset", page.Offset),
+		)
+	case !streamed && h.maxUnpaged > 0 && total > h.maxUnpaged:
+		items = items[:h.maxUnpaged]
+		truncated = true
+	}
+
+	if len(fields) > 0 {
+		span.SetAttributes(attribute.String("projection.fields", strings.Join(fields, ",")))
+		logFields["fields"] = strings.Join(fields, ",")
+	}
+
 	span.SetAttributes(
 		attribute.Int("items.count", len(items)),
+		attribute.Int("items.total", total),
+		attribute.Bool("list.truncated", truncated),
+		attribute.Bool("export.streamed", streamed),
 		attribute.String("response.status", "success"),
 	)
 
 	logFields["items_count"] = len(items)
+	logFields["streamed"] = streamed
+
+	if streamed {
+		written, err := streamItems(c, items, fields)
+		if err != nil {
+			span.RecordError(err)
+			span.SetAttributes(
+				attribute.String("error.type", "stream_error"),
+				attribute.Int("items.streamed", written),
+			)
+
+			h.logger.WithFields(logFields).WithError(err).Warn("Item stream interrupted")
+			return
+		}
+		span.SetAttributes(attribute.Int("items.streamed", written))
+		h.logger.WithFields(logFields).Info("Items streamed successfully")
+		return
+	}
+
+	// Projection runs last so only items that are sent get re-encoded
+	var data any = items
+	if len(fields) > 0 {
+		projected, err := projectItems(items, fields)
+		if err != nil {
+			span.RecordError(err)
+			span.SetAttributes(attribute.String("error.type", "projection_error"))
+
+			h.logger.WithFields(logFields).WithError(err).Error("Failed to project items")
+			writeJSON(c, http.StatusInternalServerError, gin.H{"error": "Failed to project items"})
+			return
+		}
+		data = projected
+	}
+
+	meta := gin.H{"count": len(items)}
+	switch {
+	case truncated:
+		logFields["total"] = total
+		h.logger.WithFields(logFields).Warn("Unpaged item list truncated")
+
+		meta["total"] = total
+		meta["truncated"] = true
+		meta["hint"] = fmt.Sprintf("showing the first %d of %d items; pass limit and offset to page through the rest", len(items), total)
+		respondOK(c, data, meta)
+		return
+	case page.paged():
+		meta["total"] = total
+		meta["offset"] = page.Offset
+	}
+
 	h.logger.WithFields(logFields).Info("Items retrieved successfully")
 
-	c.JSON(http.StatusOK, gin.H{"items": items, "count": len(items)})
+	respondOK(c, data, meta)
+}
+
+// wantsNDJSON reports whether the client asked for a newline-delimited JSON stream
+func wantsNDJSON(c *gin.Context) bool {
+	if c.Query("stream") == "true" {
+		return true
+	}
+	return strings.Contains(c.GetHeader("Accept"), "application/x-ndjson")
+}
+
+// streamItems writes each item as its own JSON line, projected to fields
+// unless it is empty, flushing after every line so the response is never
+// buffered in full. Items are projected one at a time as they are written.
+// It returns the number of items written.
+func streamItems(c *gin.Context, items []*models.Item, fields []string) (int, error) {
+	c.Header("Content-Type", "application/x-ndjson")
+	c.Status(http.StatusOK)
+
+	encoder := json.NewEncoder(c.Writer)
+	for i, item := range items {
+		if err := c.Request.Context().Err(); err != nil {
+			return i, err
+		}
+		var line any = item
+		if len(fields) > 0 {
+			projected, err := projectItem(item, fields)
+			if err != nil {
+				return i, err
+			}
+			line = projected
+		}
+		if err := encoder.Encode(line); err != nil {
+			return i, err
+		}
+		c.Writer.Flush()
+	}
+	return len(items), nil
 }
 
 // GetItem handles GET /api/v1/items/:id
 func (h *ItemHandler) GetItem(c *gin.Context) {
-	ctx, span := tracer.Start(c.Request.Context(), "handler.get_item")
+	ctx, span := startSpan(c, "handler.get_item")
 	defer span.End()
 
 	id := c.Param("id")
 	span.SetAttributes(attribute.String("item.id", id))
 
 	spanCtx := trace.SpanContextFromContext(ctx)
-	logFields := logrus.Fields{
+	logFields := withTraceSampled(spanCtx, logrus.Fields{
 		"trace_id": spanCtx.TraceID().String(),
 		"span_id":  spanCtx.SpanID().String(),
 		"method":   "GET",
 		"endpoint": "/api/v1/items/:id",
 		"item_id":  id,
-	}
+	})
+	tenant := resolveTenant(c, span, logFields)
 
 	item, err := h.storage.GetByID(ctx, id)
+	if err == nil && !tenant.canSee(item) {
+		// Hide other tenants' items as if they did not exist
+		err = storage.ErrItemNotFound
+	}
+	recordLookup(ctx, "get", err)
 	if err != nil {
-		if err == storage.ErrItemNotFound {
-			span.SetAttributes(
-				attribute.String("error.type", "not_found"),
-				attribute.Bool("item.found", false),
-			)
-			
-			h.logger.WithFields(logFields).Warn("Item not found")
-			c.JSON(http.StatusNotFound, gin.H{"error": "Item not found"})
-			return
-		}
-
-		span.RecordError(err)
-		span.SetAttributes(attribute.String("error.type", "storage_error"))
-		
-		h.logger.WithFields(logFields).WithError(err).Error("Failed to retrieve item")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve item"})
+		writeStorageError(c, h.logger, span, logFields, err, "Failed to retrieve item")
 		return
 	}
 
 	span.SetAttributes(
 		attribute.Bool("item.found", true),
-		attribute.String("item.name", item.Name),
+		attribute.Bool("item.flagged", item.Flagged),
 		attribute.String("response.status", "success"),
 	)
+	telemetry.SetDetail(span, attribute.String("item.name", item.Name))
 
 	logFields["item_name"] = item.Name
 	h.logger.WithFields(logFields).Info("Item retrieved successfully")
 
-	c.JSON(http.StatusOK, item)
+	c.Header("ETag", itemETag(item))
+	respondOK(c, item, nil)
+}
+
+// GetItemHistory handles GET /api/v1/items/:id/history
+func (h *ItemHandler) GetItemHistory(c *gin.Context) {
+	ctx, span := startSpan(c, "handler.get_item_history")
+	defer span.End()
+
+	id := c.Param("id")
+	span.SetAttributes(attribute.String("item.id", id))
+
+	spanCtx := trace.SpanContextFromContext(ctx)
+	logFields := withTraceSampled(spanCtx, logrus.Fields{
+		"trace_id": spanCtx.TraceID().String(),
+		"span_id":  spanCtx.SpanID().String(),
+		"method":   "GET",
+		"endpoint": "/api/v1/items/:id/history",
+		"item_id":  id,
+	})
+	tenant := resolveTenant(c, span, logFields)
+
+	var err error
+	if tenant.scoped() {
+		var item *models.Item
+		item, err = h.storage.GetByID(ctx, id)
+		if err == nil && !tenant.canSee(item) {
+			err = storage.ErrItemNotFound
+		}
+	}
+
+	var versions []models.Item
+	if err == nil {
+		versions, err = h.storage.History(ctx, id)
+	}
+	recordLookup(ctx, "history", err)
+	if err != nil {
+		writeStorageError(c, h.logger, span, logFields, err, "Failed to retrieve item history")
+		return
+	}
+
+	span.SetAttributes(
+		attribute.Bool("item.found", true),
+		attribute.Int("history.versions", len(versions)),
+		attribute.String("response.status", "success"),
+	)
+
+	logFields["history_versions"] = len(versions)
+	h.logger.WithFields(logFields).Info("Item history retrieved successfully")
+
+	respondOK(c, gin.H{"id": id, "versions": versions}, gin.H{"count": len(versions)})
 }
 
-// UpdateItem handles PUT /api/v1/items/:id
-func (h *ItemHandler) UpdateItem(c *gin.Context) {
-	ctx, span := tracer.Start(c.Request.Context(), "handler.update_item")
+// UpsertItem handles PUT /api/v1/items/:id. It replaces the item when it
+// exists and creates it at that ID when it does not.
+func (h *ItemHandler) UpsertItem(c *gin.Context) {
+	ctx, span := startSpan(c, "handler.upsert_item")
 	defer span.End()
 
 	id := c.Param("id")
 	span.SetAttributes(attribute.String("item.id", id))
 
 	spanCtx := trace.SpanContextFromContext(ctx)
-	logFields := logrus.Fields{
+	logFields := withTraceSampled(spanCtx, logrus.Fields{
 		"trace_id": spanCtx.TraceID().String(),
 		"span_id":  spanCtx.SpanID().String(),
 		"method":   "PUT",
 		"endpoint": "/api/v1/items/:id",
 		"item_id":  id,
-	}
+	})
+	tenant := resolveTenant(c, span, logFields)
 
 	var req struct {
-		Name        string `json:"name"`
-		Description string `json:"description"`
+		ID          string         `json:"id"`
+		Name        string         `json:"name"`
+		Description string         `json:"description"`
+		Metadata    map[string]any `json:"metadata"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
 		span.RecordError(err)
 		span.SetAttributes(attribute.String("error.type", "validation_error"))
+		recordBindError(ctx, err)
 		
 		h.logger.WithFields(logFields).WithError(err).Error("Invalid request payload")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload"})
+		writeJSON(c, http.StatusBadRequest, gin.H{"error": "Invalid request payload"})
 		return
 	}
 
-	span.SetAttributes(
-		attribute.String("item.new_name", req.Name),
-		attribute.String("item.new_description", req.Description),
+	if req.ID != "" && req.ID != id {
+		span.SetAttributes(attribute.String("error.type", "id_mismatch"))
+
+		h.logger.WithFields(logFields).WithField("body_id", req.ID).Warn("Item ID in body does not match URL")
+		writeJSON(c, http.StatusBadRequest, gin.H{"error": "Item ID in body does not match URL"})
+		return
+	}
+
+	item := models.NewItemWithID(id, req.Name, req.Description)
+	item.Owner = tenant.ID
+	item.Metadata = req.Metadata
+	if fieldErrs := item.Validate(); len(fieldErrs) > 0 {
+		span.SetAttributes(
+			attribute.String("error.type", "validation_error"),
+			attribute.String("validation.field", fieldErrs[0].Field),
+		)
+		recordFieldErrors(ctx, fieldErrs)
+
+		h.logger.WithFields(logFields).WithField("field_errors", fieldErrs).Error("Invalid request payload")
+		writeJSON(c, http.StatusBadRequest, gin.H{"error": fieldErrs[0].Message})
+		return
+	}
+	truncated, _ := item.Normalize()
+	span.SetAttributes(attribute.Bool("description.truncated", truncated))
+
+	telemetry.SetDetail(span,
+		attribute.String("item.new_name", item.Name),
+		attribute.String("item.new_description", item.Description),
 	)
 
-	updatedItem, err := h.storage.Update(ctx, id, req.Name, req.Description)
-	if err != nil {
-		if err == storage.ErrItemNotFound {
-			span.SetAttributes(
-				attribute.String("error.type", "not_found"),
-				attribute.Bool("item.found", false),
-			)
-			
-			h.logger.WithFields(logFields).Warn("Item not found for update")
-			c.JSON(http.StatusNotFound, gin.H{"error": "Item not found"})
+	// Scoped tenants may not overwrite items they cannot see
+	if tenant.scoped() {
+		existing, err := h.storage.GetByID(ctx, id)
+		if err == nil && !tenant.canSee(existing) {
+			span.SetAttributes(attribute.String("error.type", "not_found"))
+
+			h.logger.WithFields(logFields).Warn("Item belongs to another tenant")
+			writeJSON(c, http.StatusNotFound, gin.H{"error": "Item not found"})
 			return
 		}
+	}
 
-		span.RecordError(err)
-		span.SetAttributes(attribute.String("error.type", "storage_error"))
-		
-		h.logger.WithFields(logFields).WithError(err).Error("Failed to update item")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update item"})
+	upserted, created, err := h.storage.Upsert(ctx, id, item)
+	if err != nil {
+		writeStorageError(c, h.logger, span, logFields, err, "Failed to upsert item")
 		return
 	}
 
 	span.SetAttributes(
-		attribute.Bool("item.found", true),
-		attribute.String("item.updated_name", updatedItem.Name),
+		attribute.Bool("upsert.created", created),
 		attribute.String("response.status", "success"),
 	)
+	telemetry.SetDetail(span, attribute.String("item.updated_name", upserted.Name))
 
-	logFields["item_name"] = updatedItem.Name
-	h.logger.WithFields(logFields).Info("Item updated successfully")
+	logFields["item_name"] = upserted.Name
+	logFields["created"] = created
+	if created {
+		h.logger.WithFields(logFields).Info("Item created by upsert")
+		respondCreated(c, upserted, nil)
+		return
+	}
+	h.logger.WithFields(logFields).Info("Item replaced successfully")
 
-	c.JSON(http.StatusOK, updatedItem)
+	respondOK(c, upserted, nil)
 }
 
 // DeleteItem handles DELETE /api/v1/items/:id
 func (h *ItemHandler) DeleteItem(c *gin.Context) {
-	ctx, span := tracer.Start(c.Request.Context(), "handler.delete_item")
+	ctx, span := startSpan(c, "handler.delete_item")
 	defer span.End()
 
 	id := c.Param("id")
 	span.SetAttributes(attribute.String("item.id", id))
 
 	spanCtx := trace.SpanContextFromContext(ctx)
-	logFields := logrus.Fields{
+	logFields := withTraceSampled(spanCtx, logrus.Fields{
 		"trace_id": spanCtx.TraceID().String(),
 		"span_id":  spanCtx.SpanID().String(),
 		"method":   "DELETE",
 		"endpoint": "/api/v1/items/:id",
 		"item_id":  id,
+	})
+	tenant := resolveTenant(c, span, logFields)
+
+	// Scoped tenants may not delete items they cannot see
+	var err error
+	if tenant.scoped() {
+		var existing *models.Item
+		existing, err = h.storage.GetByID(ctx, id)
+		if err == nil && !tenant.canSee(existing) {
+			err = storage.ErrItemNotFound
+		}
 	}
-
-	err := h.storage.Delete(ctx, id)
+	if err == nil {
+		err = h.storage.Delete(ctx, id)
+	}
+	recordLookup(ctx, "delete", err)
 	if err != nil {
-		if err == storage.ErrItemNotFound {
-			span.SetAttributes(
-				attribute.String("error.type", "not_found"),
-				attribute.Bool("item.found", false),
-			)
-			
-			h.logger.WithFields(logFields).Warn("Item not found for deletion")
-			c.JSON(http.StatusNotFound, gin.H{"error": "Item not found"})
-			return
-		}
-
-		span.RecordError(err)
-		span.SetAttributes(attribute.String("error.type", "storage_error"))
-		
-		h.logger.WithFields(logFields).WithError(err).Error("Failed to delete item")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete item"})
+		writeStorageError(c, h.logger, span, logFields, err, "Failed to delete item")
 		return
 	}
 
@@ -282,23 +632,89 @@ func (h *ItemHandler) DeleteItem(c *gin.Context) {
 
 	h.logger.WithFields(logFields).Info("Item deleted successfully")
 
-	c.JSON(http.StatusOK, gin.H{"message": "Item deleted successfully"})
+	respondOK(c, gin.H{"message": "Item deleted successfully"}, nil)
 }
 
-// HealthCheck handles GET /health
+// HealthCheck handles GET /health. A deep check probes every dependency and
+// counts the items; a shallow one only shows the process is serving, without
+// touching storage. ?deep=true or false overrides HEALTH_CHECK_DEEP per probe,
+// e.g. a shallow liveness probe next to a deep readiness one.
 func (h *ItemHandler) HealthCheck(c *gin.Context) {
-	ctx, span := tracer.Start(c.Request.Context(), "handler.health_check")
+	ctx, span := startSpan(c, "handler.health_check")
 	defer span.End()
 
 	spanCtx := trace.SpanContextFromContext(ctx)
-	logFields := logrus.Fields{
+	logFields := withTraceSampled(spanCtx, logrus.Fields{
 		"trace_id": spanCtx.TraceID().String(),
 		"span_id":  spanCtx.SpanID().String(),
 		"method":   "GET",
 		"endpoint": "/health",
+	})
+
+	deep := h.deepHealth
+	if value, err := strconv.ParseBool(c.Query("deep")); err == nil {
+		deep = value
+	}
+	span.SetAttributes(attribute.Bool("health.deep", deep))
+	logFields["deep"] = deep
+
+	if !deep {
+		span.SetAttributes(attribute.String("health.status", "healthy"))
+
+		h.logger.WithFields(logFields).Info("Health check passed")
+		respondOK(c, gin.H{
+			"status":  "healthy",
+			"service": "eks-otel-demo",
+		}, nil)
+		return
+	}
+
+	// Probe every dependency; any critical one being down fails the check
+	dependencies := gin.H{}
+	healthy := true
+	for _, dep := range h.dependencies() {
+		start := time.Now()
+		err := dep.ping(ctx)
+		latency := time.Since(start)
+
+		status := "up"
+		if err != nil {
+			status = "down"
+			span.RecordError(err)
+			logFields["dependency_"+dep.name+"_error"] = err.Error()
+			if dep.critical {
+				healthy = false
+			}
+		}
+
+		latencyMs := float64(latency) / float64(time.Millisecond)
+		span.SetAttributes(
+			attribute.String("health.dependency."+dep.name+".status", status),
+			attribute.Float64("health.dependency."+dep.name+".latency_ms", latencyMs),
+		)
+		dependencies[dep.name] = gin.H{
+			"status":     status,
+			"critical":   dep.critical,
+			"latency_ms": latencyMs,
+		}
+	}
+	logFields["dependencies"] = dependencies
+
+	if !healthy {
+		span.SetAttributes(
+			attribute.String("health.status", "unhealthy"),
+			attribute.String("error.type", "dependency_down"),
+		)
+
+		h.logger.WithFields(logFields).Error("Health check failed")
+		writeJSON(c, http.StatusServiceUnavailable, gin.H{
+			"status":       "unhealthy",
+			"error":        "Critical dependency unavailable",
+			"dependencies": dependencies,
+		})
+		return
 	}
 
-	// Check storage health by counting items
 	count, err := h.storage.Count(ctx)
 	if err != nil {
 		span.RecordError(err)
@@ -308,9 +724,10 @@ func (h *ItemHandler) HealthCheck(c *gin.Context) {
 		)
 		
 		h.logger.WithFields(logFields).WithError(err).Error("Health check failed")
-		c.JSON(http.StatusServiceUnavailable, gin.H{
-			"status": "unhealthy",
-			"error":  "Storage unavailable",
+		writeJSON(c, http.StatusServiceUnavailable, gin.H{
+			"status":       "unhealthy",
+			"error":        "Storage unavailable",
+			"dependencies": dependencies,
 		})
 		return
 	}
@@ -323,9 +740,24 @@ func (h *ItemHandler) HealthCheck(c *gin.Context) {
 	logFields["item_count"] = count
 	h.logger.WithFields(logFields).Info("Health check passed")
 
-	c.JSON(http.StatusOK, gin.H{
-		"status":     "healthy",
-		"item_count": count,
-		"service":    "eks-otel-demo",
-	})
+	respondOK(c, gin.H{
+		"status":       "healthy",
+		"item_count":   count,
+		"service":      "eks-otel-demo",
+		"dependencies": dependencies,
+	}, nil)
+}
+
+// dependency is an external system the service relies on
+type dependency struct {
+	name     string
+	critical bool
+	ping     func(ctx context.Context) error
+}
+
+// dependencies lists what HealthCheck probes
+func (h *ItemHandler) dependencies() []dependency {
+	return []dependency{
+		{name: "storage", critical: true, ping: h.storage.Ping},
+	}
 }
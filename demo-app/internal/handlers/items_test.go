@@ -0,0 +1,95 @@
+package handlers_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/misua/eks-with-otel/demo-app/internal/models"
+	"github.com/misua/eks-with-otel/demo-app/internal/storage"
+)
+
+func TestCreateItemConflict(t *testing.T) {
+	// The store holds ID 1, created with an explicit ID the sequence was
+	// never advanced past
+	models.SetIDStrategy(models.IDStrategySequential)
+	t.Cleanup(func() { models.SetIDStrategy(models.IDStrategyUUID) })
+
+	store := storage.NewMemoryStorage()
+	if _, err := store.Create(context.Background(), models.NewItemWithID("1", "existing", "kept")); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+	srv := newTestServerWithStore(t, store)
+
+	tests := []struct {
+		name       string
+		wantStatus int
+		wantID     string
+	}{
+		{"taken ID", http.StatusConflict, ""},
+		{"next ID", http.StatusCreated, "2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := do(t, srv, http.MethodPost, "/api/v1/items", nil, map[string]string{"name": "new", "description": "item"})
+			if status != tt.wantStatus {
+				t.Fatalf("status %d, want %d (body %v)", status, tt.wantStatus, body)
+			}
+			if tt.wantID != "" && body["id"] != tt.wantID {
+				t.Errorf("id = %v, want %s", body["id"], tt.wantID)
+			}
+		})
+	}
+
+	status, body := do(t, srv, http.MethodGet, "/api/v1/items/1", nil, nil)
+	if status != http.StatusOK || body["name"] != "existing" || body["description"] != "kept" {
+		t.Errorf("existing item changed by the conflicting create: %d %v", status, body)
+	}
+}
+
+func TestGetItemsStreamsProjectedLines(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		fields []string
+	}{
+		{"whole items", "", []string{"created_at", "description", "flagged", "id", "name", "updated_at", "version"}},
+		{"projected", "&fields=name,id", []string{"id", "name"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newTestServer(t)
+			ids := []string{createItem(t, srv, "", "first"), createItem(t, srv, "", "second")}
+
+			resp, err := srv.Client().Get(srv.URL + "/api/v1/items?stream=true" + tt.query)
+			if err != nil {
+				t.Fatalf("get: %v", err)
+			}
+			defer resp.Body.Close()
+			if ct := resp.Header.Get("Content-Type"); ct != "application/x-ndjson" {
+				t.Fatalf("Content-Type %q, want application/x-ndjson", ct)
+			}
+
+			var got []string
+			lines := bufio.NewScanner(resp.Body)
+			for lines.Scan() {
+				var item map[string]any
+				if err := json.Unmarshal(lines.Bytes(), &item); err != nil {
+					t.Fatalf("line %q: %v", lines.Text(), err)
+				}
+				if keys := slices.Sorted(maps.Keys(item)); !slices.Equal(keys, tt.fields) {
+					t.Errorf("line fields %v, want %v", keys, tt.fields)
+				}
+				id, _ := item["id"].(string)
+				got = append(got, id)
+			}
+			if !slices.Equal(got, ids) {
+				t.Errorf("streamed IDs %v, want %v", got, ids)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	defaultLargestLimit = 10
+	maxLargestLimit     = 100
+)
+
+// GetLargestItems handles GET /api/v1/items/largest?limit=N, returning the N
+// items with the longest descriptions
+func (h *ItemHandler) GetLargestItems(c *gin.Context) {
+	ctx, span := startSpan(c, "handler.get_largest_items")
+	defer span.End()
+
+	spanCtx := trace.SpanContextFromContext(ctx)
+	logFields := withTraceSampled(spanCtx, logrus.Fields{
+		"trace_id": spanCtx.TraceID().String(),
+		"span_id":  spanCtx.SpanID().String(),
+		"method":   "GET",
+		"endpoint": "/api/v1/items/largest",
+	})
+	tenant := resolveTenant(c, span, logFields)
+
+	limit := defaultLargestLimit
+	if value := c.Query("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			span.SetAttributes(attribute.String("error.type", "validation_error"))
+
+			h.logger.WithFields(logFields).WithField("limit", value).Warn("Invalid largest limit")
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+			return
+		}
+		limit = min(n, maxLargestLimit)
+	}
+	span.SetAttributes(attribute.Int("largest.limit", limit))
+	logFields["limit"] = limit
+
+	owner := ""
+	if tenant.scoped() {
+		owner = tenant.ID
+	}
+
+	items, err := h.storage.GetLargest(ctx, limit, owner)
+	if err != nil {
+		writeStorageError(c, h.logger, span, logFields, err, "Failed to retrieve largest items")
+		return
+	}
+
+	span.SetAttributes(
+		attribute.Int("items.count", len(items)),
+		attribute.String("response.status", "success"),
+	)
+
+	logFields["items_count"] = len(items)
+	h.logger.WithFields(logFields).Info("Largest items retrieved successfully")
+
+	respondOK(c, items, gin.H{"count": len(items), "limit": limit})
+}
@@ -0,0 +1,16 @@
+package handlers
+
+import (
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// withTraceSampled adds trace_sampled to a handler's log fields so a log line
+// tells whether its trace was exported. Invalid span contexts, e.g. with
+// tracing disabled, get no flag.
+func withTraceSampled(spanCtx trace.SpanContext, fields logrus.Fields) logrus.Fields {
+	if spanCtx.IsValid() {
+		fields["trace_sampled"] = spanCtx.IsSampled()
+	}
+	return fields
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/misua/eks-with-otel/demo-app/internal/storage"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// lookupCounter counts requests that address a single item by ID, split by
+// whether the item existed
+var lookupCounter, _ = otel.Meter("handlers").Int64Counter(
+	"item.lookups",
+	metric.WithDescription("Number of item lookups by ID, by operation and result"),
+	metric.WithUnit("{lookup}"),
+)
+
+// lookupsFound and lookupsNotFound feed the not-found ratio gauge
+var lookupsFound, lookupsNotFound atomic.Int64
+
+// The ratio covers every lookup since the process started and is only
+// reported once there was one
+var _, _ = otel.Meter("handlers").Float64ObservableGauge(
+	"item.lookups.not_found_ratio",
+	metric.WithDescription("Share of item lookups by ID since startup that found no item"),
+	metric.WithFloat64Callback(func(_ context.Context, o metric.Float64Observer) error {
+		if ratio, ok := notFoundRatio(); ok {
+			o.Observe(ratio)
+		}
+		return nil
+	}),
+)
+
+// notFoundRatio returns the share of lookups that found no item, false
+// before the first lookup
+func notFoundRatio() (float64, bool) {
+	found, notFound := lookupsFound.Load(), lookupsNotFound.Load()
+	if found+notFound == 0 {
+		return 0, false
+	}
+	return float64(notFound) / float64(found+notFound), true
+}
+
+// recordLookup counts the outcome of a lookup; operation is get, history,
+// delete or clone. Storage failures say nothing about the item and are not counted.
+func recordLookup(ctx context.Context, operation string, err error) {
+	var result string
+	switch {
+	case err == nil:
+		result = "found"
+		lookupsFound.Add(1)
+	case storage.IsNotFound(err):
+		result = "not_found"
+		lookupsNotFound.Add(1)
+	default:
+		return
+	}
+	lookupCounter.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("operation", operation),
+		attribute.String("result", result),
+	))
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/misua/eks-with-otel/demo-app/internal/storage"
+)
+
+func TestNotFoundRatio(t *testing.T) {
+	notFound := fmt.Errorf("get 1: %w", storage.ErrItemNotFound)
+	tests := []struct {
+		name    string
+		lookups []error
+		want    float64
+		wantOK  bool
+	}{
+		{"no lookups", nil, 0, false},
+		{"all found", []error{nil, nil}, 0, true},
+		{"one in four missing", []error{nil, notFound, nil, nil}, 0.25, true},
+		{"storage failures not counted", []error{notFound, errors.New("boom")}, 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lookupsFound.Store(0)
+			lookupsNotFound.Store(0)
+			for _, err := range tt.lookups {
+				recordLookup(context.Background(), "get", err)
+			}
+			got, ok := notFoundRatio()
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("notFoundRatio() = %v, %v; want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/misua/eks-with-otel/demo-app/internal/models"
+)
+
+// listPage is the slice of a listing requested with ?limit=N&offset=M.
+// A zero Limit means no page was asked for.
+type listPage struct {
+	Limit  int
+	Offset int
+}
+
+// parseListPage reads the limit and offset list query parameters
+func parseListPage(c *gin.Context) (listPage, error) {
+	var page listPage
+	if value := c.Query("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			return page, fmt.Errorf("limit must be a positive integer")
+		}
+		page.Limit = n
+	}
+	if value := c.Query("offset"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return page, fmt.Errorf("offset must be a non-negative integer")
+		}
+		page.Offset = n
+	}
+	return page, nil
+}
+
+// paged reports whether the client asked for a page rather than everything
+func (p listPage) paged() bool {
+	return p.Limit > 0 || p.Offset > 0
+}
+
+// applyPage cuts items down to the page, with an open ended limit when only
+// an offset was given
+func applyPage(items []*models.Item, p listPage) []*models.Item {
+	start := min(p.Offset, len(items))
+	end := len(items)
+	if p.Limit > 0 {
+		end = min(start+p.Limit, len(items))
+	}
+	return items[start:end]
+}
@@ -0,0 +1,244 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/misua/eks-with-otel/demo-app/internal/models"
+	"github.com/misua/eks-with-otel/demo-app/internal/storage"
+	"github.com/misua/eks-with-otel/demo-app/internal/telemetry"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Patch formats recorded as patch.format, picked by the request Content-Type
+const (
+	patchFormatMerge  = "merge-patch"
+	patchFormatJSON   = "json-patch"
+	patchFormatSimple = "simple"
+)
+
+var patchFormats = map[string]string{
+	"application/merge-patch+json": patchFormatMerge,
+	"application/json-patch+json":  patchFormatJSON,
+	"application/json":             patchFormatSimple,
+}
+
+// acceptPatch lists the supported PATCH media types for the Accept-Patch header
+const acceptPatch = "application/merge-patch+json, application/json-patch+json, application/json"
+
+// patchError is a patch that cannot be applied to the item, answered with
+// its own status rather than through errorToStatus
+type patchError struct {
+	status  int
+	errType string
+	message string
+}
+
+func (e *patchError) Error() string {
+	return e.message
+}
+
+// PatchItem handles PATCH /api/v1/items/:id. The Content-Type selects the
+// format: an RFC 7386 merge patch, an RFC 6902 JSON Patch, or plain JSON
+// setting name and/or description. Name, description and metadata are the
+// only patchable fields. An If-Match header makes the patch conditional on
+// the item's version.
+func (h *ItemHandler) PatchItem(c *gin.Context) {
+	ctx, span := startSpan(c, "handler.patch_item")
+	defer span.End()
+
+	id := c.Param("id")
+	span.SetAttributes(attribute.String("item.id", id))
+
+	spanCtx := trace.SpanContextFromContext(ctx)
+	logFields := withTraceSampled(spanCtx, logrus.Fields{
+		"trace_id": spanCtx.TraceID().String(),
+		"span_id":  spanCtx.SpanID().String(),
+		"method":   "PATCH",
+		"endpoint": "/api/v1/items/:id",
+		"item_id":  id,
+	})
+	tenant := resolveTenant(c, span, logFields)
+
+	format, ok := patchFormats[c.ContentType()]
+	if !ok {
+		span.SetAttributes(attribute.String("error.type", "unsupported_media_type"))
+
+		h.logger.WithFields(logFields).WithField("content_type", c.ContentType()).Warn("Unsupported patch format")
+		c.Header("Accept-Patch", acceptPatch)
+		writeJSON(c, http.StatusUnsupportedMediaType, gin.H{"error": "Content-Type must be one of " + acceptPatch})
+		return
+	}
+	span.SetAttributes(attribute.String("patch.format", format))
+	logFields["patch_format"] = format
+
+	body, err := io.ReadAll(c.Request.Body)
+	var edit func(doc any) (any, error)
+	if err == nil {
+		edit, err = parsePatch(format, body)
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetAttributes(attribute.String("error.type", "validation_error"))
+		recordBindError(ctx, err)
+
+		h.logger.WithFields(logFields).WithError(err).Error("Invalid request payload")
+		writeJSON(c, http.StatusBadRequest, gin.H{"error": "Invalid request payload: " + err.Error()})
+		return
+	}
+
+	expected, err := parseIfMatch(c)
+	if err != nil {
+		span.SetAttributes(attribute.String("error.type", "validation_error"))
+
+		h.logger.WithFields(logFields).WithError(err).Warn("Invalid If-Match header")
+		writeJSON(c, http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if expected > 0 {
+		span.SetAttributes(attribute.Int("item.expected_version", expected))
+		logFields["expected_version"] = expected
+	}
+
+	var truncated bool
+	patched, err := h.storage.PatchItem(ctx, id, func(item *models.Item) error {
+		// Items of other tenants are reported as not found without being touched
+		if !tenant.canSee(item) {
+			return fmt.Errorf("patch %s: %w", id, storage.ErrItemNotFound)
+		}
+		if err := storage.CheckVersion(item, expected); err != nil {
+			return err
+		}
+
+		doc, err := edit(editableDocument(item))
+		if errors.Is(err, errPatchTestFailed) {
+			return &patchError{status: http.StatusConflict, errType: "patch_test_failed", message: err.Error()}
+		}
+		if err != nil {
+			return &patchError{status: http.StatusUnprocessableEntity, errType: "patch_not_applicable", message: err.Error()}
+		}
+		name, description, metadata, err := documentFields(doc)
+		if err != nil {
+			return &patchError{status: http.StatusUnprocessableEntity, errType: "patch_not_applicable", message: err.Error()}
+		}
+
+		item.Replace(name, description, metadata)
+		if fieldErrs := item.Validate(); len(fieldErrs) > 0 {
+			span.SetAttributes(attribute.String("validation.field", fieldErrs[0].Field))
+			recordFieldErrors(ctx, fieldErrs)
+			return &patchError{status: http.StatusBadRequest, errType: "validation_error", message: fieldErrs[0].Message}
+		}
+		truncated, _ = item.Normalize()
+		return nil
+	})
+
+	var perr *patchError
+	if errors.As(err, &perr) {
+		span.RecordError(err)
+		span.SetAttributes(attribute.String("error.type", perr.errType))
+
+		h.logger.WithFields(logFields).WithError(err).Warn("Patch cannot be applied")
+		writeJSON(c, perr.status, gin.H{"error": perr.message})
+		return
+	}
+	if err != nil {
+		writeStorageError(c, h.logger, span, logFields, err, "Failed to patch item")
+		return
+	}
+
+	span.SetAttributes(
+		attribute.Bool("item.found", true),
+		attribute.Bool("description.truncated", truncated),
+		attribute.String("response.status", "success"),
+	)
+	telemetry.SetDetail(span, attribute.String("item.updated_name", patched.Name))
+
+	logFields["item_name"] = patched.Name
+	h.logger.WithFields(logFields).Info("Item patched successfully")
+
+	c.Header("ETag", itemETag(patched))
+	respondOK(c, patched, nil)
+}
+
+// parsePatch decodes a patch body of the given format into a function that
+// applies it to an item's editable document
+func parsePatch(format string, body []byte) (func(doc any) (any, error), error) {
+	switch format {
+	case patchFormatMerge:
+		var patch map[string]any
+		if err := json.Unmarshal(body, &patch); err != nil {
+			return nil, err
+		}
+		return func(doc any) (any, error) { return mergePatch(doc, patch), nil }, nil
+	case patchFormatJSON:
+		var ops []jsonPatchOp
+		if err := json.Unmarshal(body, &ops); err != nil {
+			return nil, err
+		}
+		return func(doc any) (any, error) { return applyJSONPatch(doc, ops) }, nil
+	}
+
+	var patch models.ItemPatch
+	if err := json.Unmarshal(body, &patch); err != nil {
+		return nil, err
+	}
+	if patch.IsEmpty() {
+		return nil, errors.New("patch must set at least one field")
+	}
+	return func(doc any) (any, error) {
+		fields := doc.(map[string]any)
+		if patch.Name != nil {
+			fields["name"] = *patch.Name
+		}
+		if patch.Description != nil {
+			fields["description"] = *patch.Description
+		}
+		return fields, nil
+	}, nil
+}
+
+// editableDocument is the JSON view of the patchable fields of an item. It
+// is built by a JSON round trip so patches never alias the stored metadata.
+func editableDocument(item *models.Item) map[string]any {
+	data, _ := json.Marshal(struct {
+		Name        string         `json:"name"`
+		Description string         `json:"description"`
+		Metadata    map[string]any `json:"metadata,omitempty"`
+	}{item.Name, item.Description, item.Metadata})
+
+	var doc map[string]any
+	json.Unmarshal(data, &doc)
+	return doc
+}
+
+// documentFields reads the patchable fields back from a patched document.
+// Removed fields come back empty.
+func documentFields(doc any) (name, description string, metadata map[string]any, err error) {
+	fields, ok := doc.(map[string]any)
+	if !ok {
+		return "", "", nil, errors.New("patched item must be a JSON object")
+	}
+	for key, value := range fields {
+		switch key {
+		case "name":
+			name, ok = value.(string)
+		case "description":
+			description, ok = value.(string)
+		case "metadata":
+			metadata, ok = value.(map[string]any)
+			ok = ok || value == nil
+		default:
+			return "", "", nil, fmt.Errorf("field %q cannot be patched", key)
+		}
+		if !ok {
+			return "", "", nil, fmt.Errorf("field %q has the wrong type", key)
+		}
+	}
+	return name, description, metadata, nil
+}
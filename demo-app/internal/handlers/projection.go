@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/misua/eks-with-otel/demo-app/internal/models"
+)
+
+// itemJSONFields are the JSON names of the Item fields, the only names
+// ?fields= accepts. They are read from the struct tags so new fields become
+// projectable without touching this file.
+var itemJSONFields = func() []string {
+	var names []string
+	t := reflect.TypeOf(models.Item{})
+	for i := range t.NumField() {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			names = append(names, name)
+		}
+	}
+	return names
+}()
+
+// parseFields reads the ?fields=id,name projection, rejecting names that are
+// not Item fields. No parameter means whole items.
+func parseFields(c *gin.Context) ([]string, error) {
+	value := c.Query("fields")
+	if value == "" {
+		return nil, nil
+	}
+	var fields []string
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" || slices.Contains(fields, field) {
+			continue
+		}
+		if !slices.Contains(itemJSONFields, field) {
+			return nil, fmt.Errorf("unknown field %q in fields, must be one of %s", field, strings.Join(itemJSONFields, ", "))
+		}
+		fields = append(fields, field)
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("fields must name at least one field")
+	}
+	return fields, nil
+}
+
+// projectItems keeps only the requested fields of each item, see projectItem
+func projectItems(items []*models.Item, fields []string) ([]map[string]json.RawMessage, error) {
+	projected := make([]map[string]json.RawMessage, 0, len(items))
+	for _, item := range items {
+		kept, err := projectItem(item, fields)
+		if err != nil {
+			return nil, err
+		}
+		projected = append(projected, kept)
+	}
+	return projected, nil
+}
+
+// projectItem keeps only the requested fields of an item. It goes through
+// its regular JSON form first, so projected values are written exactly as in
+// a full item, TIME_FORMAT included; fields the item omits, such as an empty
+// owner, stay absent.
+func projectItem(item *models.Item, fields []string) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return nil, fmt.Errorf("project item %s: %w", item.ID, err)
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, fmt.Errorf("project item %s: %w", item.ID, err)
+	}
+	kept := make(map[string]json.RawMessage, len(fields))
+	for _, field := range fields {
+		if value, ok := all[field]; ok {
+			kept[field] = value
+		}
+	}
+	return kept, nil
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Readiness tracks whether the service has finished starting up. The server
+// listens before it is ready so liveness probes pass during slow startups,
+// while /readyz keeps traffic away until MarkReady is called.
+type Readiness struct {
+	ready atomic.Bool
+}
+
+// MarkReady flips the service to ready
+func (r *Readiness) MarkReady() {
+	r.ready.Store(true)
+}
+
+// Ready reports whether MarkReady has been called
+func (r *Readiness) Ready() bool {
+	return r.ready.Load()
+}
+
+// Handler serves GET /readyz: 200 once ready, 503 before
+func (r *Readiness) Handler(c *gin.Context) {
+	_, span := startSpan(c, "handler.readiness")
+	defer span.End()
+
+	ready := r.Ready()
+	span.SetAttributes(attribute.Bool("service.ready", ready))
+	if !ready {
+		writeJSON(c, http.StatusServiceUnavailable, gin.H{"status": "starting"})
+		return
+	}
+	writeJSON(c, http.StatusOK, gin.H{"status": "ready"})
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ResponseOptions controls how responses are written
+type ResponseOptions struct {
+	// Envelope wraps every successful response as {"data": ..., "meta": {...}}
+	Envelope bool
+	// Pretty indents all JSON responses, errors included, for reading in a browser
+	Pretty bool
+}
+
+var responseOptions ResponseOptions
+
+// ConfigureResponses sets the response format used by all handlers.
+// It is meant to be called once at startup, before serving traffic.
+func ConfigureResponses(opts ResponseOptions) {
+	responseOptions = opts
+}
+
+// writeJSON is the single place handlers serialize JSON, so every response
+// honors the Pretty option
+func writeJSON(c *gin.Context, status int, obj any) {
+	if responseOptions.Pretty {
+		c.IndentedJSON(status, obj)
+		return
+	}
+	c.JSON(status, obj)
+}
+
+// respondOK writes a 200 response with the configured format
+func respondOK(c *gin.Context, data any, meta gin.H) {
+	respond(c, http.StatusOK, data, meta)
+}
+
+// respondCreated writes a 201 response with the configured format
+func respondCreated(c *gin.Context, data any, meta gin.H) {
+	respond(c, http.StatusCreated, data, meta)
+}
+
+// respond writes a successful response either enveloped or in the flat format
+func respond(c *gin.Context, status int, data any, meta gin.H) {
+	if responseOptions.Envelope {
+		if meta == nil {
+			meta = gin.H{}
+		}
+		writeJSON(c, status, gin.H{"data": data, "meta": meta})
+		return
+	}
+	writeJSON(c, status, flatBody(data, meta))
+}
+
+// flatBody builds the backward compatible flat format: meta fields sit next to
+// the data, and collections are keyed under "items"
+func flatBody(data any, meta gin.H) any {
+	if len(meta) == 0 {
+		return data
+	}
+
+	body := gin.H{}
+	switch d := data.(type) {
+	case gin.H:
+		for k, v := range d {
+			body[k] = v
+		}
+	default:
+		body["items"] = d
+	}
+	for k, v := range meta {
+		body[k] = v
+	}
+	return body
+}
+
+// ServiceInfo handles GET / with the service name, version and status
+func ServiceInfo(service, version, apiPath string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		respondOK(c, gin.H{
+			"service": service,
+			"version": version,
+			"status":  "running",
+			"api":     apiPath,
+		}, nil)
+	}
+}
@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/misua/eks-with-otel/demo-app/internal/middleware"
+	"github.com/misua/eks-with-otel/demo-app/internal/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// startSpan starts a handler span under the request's span and records who
+// called, matching the client_ip, user_agent and scenario_id fields of the
+// access log so a trace can be read without the logs
+func startSpan(c *gin.Context, name string) (context.Context, trace.Span) {
+	ctx, span := tracer.Start(c.Request.Context(), name)
+
+	// Both are kept at every detail level, the IP only when SPAN_CLIENT_IP allows it
+	span.SetAttributes(attribute.String("http.user_agent", c.Request.UserAgent()))
+	if telemetry.RecordClientIP() {
+		span.SetAttributes(attribute.String("client.ip", c.ClientIP()))
+	}
+	if scenarioID := c.GetString(middleware.ScenarioKey); scenarioID != "" {
+		span.SetAttributes(attribute.String("scenario.id", scenarioID))
+	}
+	return ctx, span
+}
@@ -0,0 +1,57 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/misua/eks-with-otel/demo-app/internal/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestHandlerSpanCaller(t *testing.T) {
+	recorder := recordSpans(t)
+
+	tests := []struct {
+		name     string
+		detail   string
+		clientIP bool
+	}{
+		{"minimal detail", telemetry.DetailMinimal, false},
+		{"minimal detail with client IP", telemetry.DetailMinimal, true},
+		{"full detail", telemetry.DetailFull, false},
+		{"full detail with client IP", telemetry.DetailFull, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			telemetry.SetDetailLevel(tt.detail)
+			telemetry.SetRecordClientIP(tt.clientIP)
+			t.Cleanup(func() {
+				telemetry.SetDetailLevel(telemetry.DetailFull)
+				telemetry.SetRecordClientIP(false)
+			})
+			srv := newTestServer(t)
+
+			do(t, srv, http.MethodGet, "/api/v1/items", map[string]string{"User-Agent": "span-test"}, nil)
+
+			// The recorder is shared, the last listing span is this request's
+			var attrs map[attribute.Key]attribute.Value
+			for _, span := range recorder.Ended() {
+				if span.Name() == "handler.get_items" {
+					attrs = map[attribute.Key]attribute.Value{}
+					for _, kv := range span.Attributes() {
+						attrs[kv.Key] = kv.Value
+					}
+				}
+			}
+			if attrs == nil {
+				t.Fatal("no handler.get_items span recorded")
+			}
+			if got := attrs["http.user_agent"].AsString(); got != "span-test" {
+				t.Errorf("http.user_agent = %q, want span-test", got)
+			}
+			if _, ok := attrs["client.ip"]; ok != tt.clientIP {
+				t.Errorf("client.ip recorded = %v, want %v", ok, tt.clientIP)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/misua/eks-with-otel/demo-app/internal/models"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	tenantHeader     = "X-Tenant-ID"
+	tenantBaggageKey = "tenant.id"
+)
+
+var adminTenants []string
+
+// ConfigureTenants sets the tenant IDs that see every tenant's items. Admin
+// scope comes only from this server-side list, never from the request. It is
+// meant to be called once at startup, before serving traffic.
+func ConfigureTenants(adminIDs []string) {
+	adminTenants = adminIDs
+}
+
+// tenantScope describes which items a request is allowed to see
+type tenantScope struct {
+	ID    string
+	Admin bool
+}
+
+// scoped reports whether results must be filtered to the tenant's own items
+func (t tenantScope) scoped() bool {
+	return t.ID != "" && !t.Admin
+}
+
+// canSee reports whether the item is visible to the tenant
+func (t tenantScope) canSee(item *models.Item) bool {
+	return !t.scoped() || item.Owner == t.ID
+}
+
+// filter keeps only the items visible to the tenant
+func (t tenantScope) filter(items []*models.Item) []*models.Item {
+	if !t.scoped() {
+		return items
+	}
+	visible := make([]*models.Item, 0, len(items))
+	for _, item := range items {
+		if t.canSee(item) {
+			visible = append(visible, item)
+		}
+	}
+	return visible
+}
+
+// resolveTenant reads the tenant from the X-Tenant-ID header, falling back to
+// the tenant.id baggage member, and records it on the span and log fields.
+// Tenants listed in TENANT_ADMIN_IDS get admin scope. Requests without a
+// tenant keep the unscoped behavior.
+func resolveTenant(c *gin.Context, span trace.Span, logFields logrus.Fields) tenantScope {
+	tenant := tenantScope{ID: strings.TrimSpace(c.GetHeader(tenantHeader))}
+	if tenant.ID == "" {
+		tenant.ID = baggage.FromContext(c.Request.Context()).Member(tenantBaggageKey).Value()
+	}
+	tenant.Admin = tenant.ID != "" && slices.Contains(adminTenants, tenant.ID)
+
+	if tenant.ID != "" {
+		span.SetAttributes(
+			attribute.String("tenant.id", tenant.ID),
+			attribute.Bool("tenant.admin", tenant.Admin),
+		)
+		logFields["tenant_id"] = tenant.ID
+		if tenant.Admin {
+			logFields["tenant_admin"] = true
+		}
+	}
+	return tenant
+}
@@ -0,0 +1,72 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/misua/eks-with-otel/demo-app/internal/handlers"
+)
+
+func TestDeleteItemTenantScope(t *testing.T) {
+	handlers.ConfigureTenants([]string{"ops"})
+	t.Cleanup(func() { handlers.ConfigureTenants(nil) })
+
+	tests := []struct {
+		name       string
+		headers    map[string]string
+		wantStatus int
+		wantKept   bool
+	}{
+		{"owner", map[string]string{"X-Tenant-ID": "a"}, http.StatusOK, false},
+		{"other tenant", map[string]string{"X-Tenant-ID": "b"}, http.StatusNotFound, true},
+		{"other tenant claiming admin", map[string]string{"X-Tenant-ID": "b", "X-Tenant-Admin": "true"}, http.StatusNotFound, true},
+		{"configured admin tenant", map[string]string{"X-Tenant-ID": "ops"}, http.StatusOK, false},
+		{"no tenant", nil, http.StatusOK, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newTestServer(t)
+			id := createItem(t, srv, "a", "owned by a")
+
+			status, body := do(t, srv, http.MethodDelete, "/api/v1/items/"+id, tt.headers, nil)
+			if status != tt.wantStatus {
+				t.Fatalf("delete: status %d, want %d (body %v)", status, tt.wantStatus, body)
+			}
+
+			status, _ = do(t, srv, http.MethodGet, "/api/v1/items/"+id, map[string]string{"X-Tenant-ID": "a"}, nil)
+			if kept := status == http.StatusOK; kept != tt.wantKept {
+				t.Errorf("item kept = %v, want %v", kept, tt.wantKept)
+			}
+		})
+	}
+}
+
+func TestGetItemsAdminScope(t *testing.T) {
+	handlers.ConfigureTenants([]string{"ops"})
+	t.Cleanup(func() { handlers.ConfigureTenants(nil) })
+
+	srv := newTestServer(t)
+	createItem(t, srv, "a", "owned by a")
+	createItem(t, srv, "b", "owned by b")
+
+	tests := []struct {
+		name      string
+		headers   map[string]string
+		wantCount int
+	}{
+		{"tenant", map[string]string{"X-Tenant-ID": "a"}, 1},
+		{"admin header ignored", map[string]string{"X-Tenant-ID": "a", "X-Tenant-Admin": "true"}, 1},
+		{"configured admin tenant", map[string]string{"X-Tenant-ID": "ops"}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := do(t, srv, http.MethodGet, "/api/v1/items", tt.headers, nil)
+			if status != http.StatusOK {
+				t.Fatalf("list: status %d", status)
+			}
+			if count, _ := body["count"].(float64); int(count) != tt.wantCount {
+				t.Errorf("count = %v, want %d", body["count"], tt.wantCount)
+			}
+		})
+	}
+}
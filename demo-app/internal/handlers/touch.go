@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/misua/eks-with-otel/demo-app/internal/storage"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TouchItem handles POST /api/v1/items/:id/touch, bumping the item's
+// UpdatedAt and version without changing its fields
+func (h *ItemHandler) TouchItem(c *gin.Context) {
+	ctx, span := startSpan(c, "handler.touch_item")
+	defer span.End()
+
+	id := c.Param("id")
+	span.SetAttributes(
+		attribute.String("item.id", id),
+		attribute.Bool("touch", true),
+	)
+
+	spanCtx := trace.SpanContextFromContext(ctx)
+	logFields := withTraceSampled(spanCtx, logrus.Fields{
+		"trace_id": spanCtx.TraceID().String(),
+		"span_id":  spanCtx.SpanID().String(),
+		"method":   "POST",
+		"endpoint": "/api/v1/items/:id/touch",
+		"item_id":  id,
+	})
+	tenant := resolveTenant(c, span, logFields)
+
+	// Scoped tenants may not touch items they cannot see
+	if tenant.scoped() {
+		existing, err := h.storage.GetByID(ctx, id)
+		if err == nil && !tenant.canSee(existing) {
+			err = storage.ErrItemNotFound
+		}
+		if err != nil {
+			writeStorageError(c, h.logger, span, logFields, err, "Failed to touch item")
+			return
+		}
+	}
+
+	item, err := h.storage.Touch(ctx, id)
+	if err != nil {
+		writeStorageError(c, h.logger, span, logFields, err, "Failed to touch item")
+		return
+	}
+
+	span.SetAttributes(
+		attribute.Bool("item.found", true),
+		attribute.String("response.status", "success"),
+	)
+
+	logFields["version"] = item.Version
+	h.logger.WithFields(logFields).Info("Item touched")
+
+	respondOK(c, item, nil)
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/misua/eks-with-otel/demo-app/internal/models"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// validationFailureCounter counts rejected item payloads once per failing
+// field. Only known fields and rules become label values, everything else is
+// "other", so client input cannot grow the series count.
+var validationFailureCounter, _ = otel.Meter("handlers").Int64Counter(
+	"item.validation_failures",
+	metric.WithDescription("Number of item payload validation failures, by field and rule"),
+	metric.WithUnit("{failure}"),
+)
+
+// Label values for payloads that are not JSON at all
+const (
+	fieldPayload  = "payload"
+	ruleMalformed = "malformed"
+)
+
+// metricField maps a failing field to a bounded label value; metadata keys
+// collapse into "metadata"
+func metricField(field string) string {
+	switch {
+	case field == "name", field == "description", field == fieldPayload:
+		return field
+	case strings.HasPrefix(field, "metadata."):
+		return "metadata"
+	}
+	return "other"
+}
+
+// metricRule maps a failed rule to a bounded label value
+func metricRule(rule string) string {
+	switch rule {
+	case models.RuleRequired, models.RuleTooLong, models.RuleNotAllowed, ruleMalformed:
+		return rule
+	}
+	return "other"
+}
+
+func recordValidationFailure(ctx context.Context, field, rule string) {
+	validationFailureCounter.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("field", metricField(field)),
+		attribute.String("rule", metricRule(rule)),
+	))
+}
+
+// recordFieldErrors counts each field rejected by models.Item.Validate
+func recordFieldErrors(ctx context.Context, fieldErrs []models.FieldError) {
+	for _, fe := range fieldErrs {
+		recordValidationFailure(ctx, fe.Field, fe.Rule)
+	}
+}
+
+// recordBindError counts a payload rejected while binding: once per field
+// failing a binding tag, or once as malformed when it is not valid JSON
+func recordBindError(ctx context.Context, err error) {
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		recordValidationFailure(ctx, fieldPayload, ruleMalformed)
+		return
+	}
+	for _, fe := range fieldErrs {
+		recordValidationFailure(ctx, strings.ToLower(fe.Field()), fe.Tag())
+	}
+}
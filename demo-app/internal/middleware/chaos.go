@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"math/rand"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ChaosErrors fails the given fraction of requests with a 500 before they
+// reach a handler, for resilience demos. Failed requests carry
+// chaos.injected=true on their span so injected errors can be told apart from
+// real ones. Exempt paths such as probes are never failed, and a rate of zero
+// or less disables the middleware.
+func ChaosErrors(rate float64, exempt ...string) gin.HandlerFunc {
+	if rate <= 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	exemptPaths := make(map[string]bool, len(exempt))
+	for _, path := range exempt {
+		exemptPaths[path] = true
+	}
+
+	return func(c *gin.Context) {
+		if exemptPaths[c.Request.URL.Path] || rand.Float64() >= rate {
+			c.Next()
+			return
+		}
+
+		trace.SpanFromContext(c.Request.Context()).SetAttributes(
+			attribute.Bool("chaos.injected", true),
+			attribute.String("error.type", "chaos"),
+		)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": "Injected failure for chaos testing",
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// GzipRequests transparently decompresses request bodies sent with
+// Content-Encoding: gzip so handlers read plain JSON. Bodies that are not
+// valid gzip get a 400. maxBytes caps the decompressed size to guard against
+// gzip bombs; reads past it fail, which handlers report as a bad payload.
+// Zero or less leaves the decompressed size unbounded.
+func GzipRequests(maxBytes int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !strings.EqualFold(strings.TrimSpace(c.GetHeader("Content-Encoding")), "gzip") || c.Request.Body == nil {
+			c.Next()
+			return
+		}
+
+		span := trace.SpanFromContext(c.Request.Context())
+		span.SetAttributes(attribute.Bool("request.compressed", true))
+
+		zr, err := gzip.NewReader(c.Request.Body)
+		if err != nil {
+			span.RecordError(err)
+			span.SetAttributes(attribute.String("error.type", "invalid_gzip"))
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Malformed gzip request body"})
+			return
+		}
+		defer zr.Close()
+
+		var body io.ReadCloser = zr
+		if maxBytes > 0 {
+			body = http.MaxBytesReader(c.Writer, zr, maxBytes)
+		}
+		c.Request.Body = body
+		c.Request.Header.Del("Content-Encoding")
+		c.Request.Header.Del("Content-Length")
+		c.Request.ContentLength = -1
+
+		c.Next()
+	}
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DetachedContext returns a context for background work started by a handler.
+// It carries the request's span context and baggage, so spans started from it
+// join the request's trace, but it is not cancelled when the request ends and
+// carries no other request-scoped values.
+//
+// Sample handler:
+//
+//	func (h *ItemHandler) DeleteItem(c *gin.Context) {
+//		// ... delete the item and respond ...
+//
+//		ctx := middleware.DetachedContext(c)
+//		go func() {
+//			ctx, span := tracer.Start(ctx, "background.audit_delete")
+//			defer span.End()
+//			h.auditLog.Record(ctx, "item deleted")
+//		}()
+//	}
+//
+// Using c.Request.Context() in the goroutine instead would fail with
+// "context canceled" as soon as the response is written.
+func DetachedContext(c *gin.Context) context.Context {
+	ctx := c.Request.Context()
+
+	detached := trace.ContextWithSpanContext(context.Background(), trace.SpanContextFromContext(ctx))
+	return baggage.ContextWithBaggage(detached, baggage.FromContext(ctx))
+}
@@ -0,0 +1,96 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/misua/eks-with-otel/demo-app/internal/middleware"
+	"github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestDetachedContextInGoroutine(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	provider := sdktrace.NewTracerProvider()
+	tracer := provider.Tracer("test")
+	logger, hook := logtest.NewNullLogger()
+
+	type result struct {
+		requestTraceID string
+		requestErr     error
+		detachedErr    error
+	}
+	results := make(chan result, 1)
+
+	router := gin.New()
+	router.Use(otelgin.Middleware("test",
+		otelgin.WithTracerProvider(provider),
+		otelgin.WithPropagators(propagation.Baggage{}),
+	))
+	router.GET("/work", func(c *gin.Context) {
+		requestCtx := c.Request.Context()
+		requestTraceID := trace.SpanContextFromContext(requestCtx).TraceID().String()
+		ctx := middleware.DetachedContext(c)
+
+		go func() {
+			// Run only once the request is over, as real background work would
+			<-requestCtx.Done()
+
+			ctx, span := tracer.Start(ctx, "background.work")
+			defer span.End()
+			spanCtx := trace.SpanContextFromContext(ctx)
+			logger.WithFields(logrus.Fields{
+				"trace_id": spanCtx.TraceID().String(),
+				"tenant":   baggage.FromContext(ctx).Member("tenant.id").Value(),
+			}).Info("Background work done")
+
+			results <- result{requestTraceID, requestCtx.Err(), ctx.Err()}
+		}()
+		c.Status(http.StatusAccepted)
+	})
+
+	srv := httptest.NewServer(router)
+	defer srv.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/work", nil)
+	req.Header.Set("baggage", "tenant.id=a")
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	resp.Body.Close()
+
+	var got result
+	select {
+	case got = <-results:
+	case <-time.After(5 * time.Second):
+		t.Fatal("background work never ran")
+	}
+
+	if got.requestErr != context.Canceled {
+		t.Errorf("request context error = %v, want it cancelled", got.requestErr)
+	}
+	if got.detachedErr != nil {
+		t.Errorf("detached context error = %v, want none", got.detachedErr)
+	}
+
+	entry := hook.LastEntry()
+	if entry == nil {
+		t.Fatal("background work logged nothing")
+	}
+	if traceID := entry.Data["trace_id"]; traceID != got.requestTraceID {
+		t.Errorf("logged trace_id = %v, want the request's %s", traceID, got.requestTraceID)
+	}
+	if tenant := entry.Data["tenant"]; tenant != "a" {
+		t.Errorf("logged tenant = %v, want baggage to carry a", tenant)
+	}
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// failoverExporter sends spans to one of several collectors. It sticks with
+// the active collector until an export fails, then tries the others in order
+// and makes the first that accepts the batch the new active one.
+type failoverExporter struct {
+	endpoints []string
+	exporters []sdktrace.SpanExporter
+	active    atomic.Int64
+	logger    *logrus.Logger
+
+	// exported counts spans accepted by a collector
+	exported atomic.Int64
+}
+
+func newFailoverExporter(endpoints []string, exporters []sdktrace.SpanExporter, active int, logger *logrus.Logger) *failoverExporter {
+	f := &failoverExporter{endpoints: endpoints, exporters: exporters, logger: logger}
+	f.active.Store(int64(active))
+	return f
+}
+
+// ExportSpans implements sdktrace.SpanExporter
+func (f *failoverExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	start := int(f.active.Load())
+	var err error
+	for i := range f.exporters {
+		idx := (start + i) % len(f.exporters)
+		if err = f.exporters[idx].ExportSpans(ctx, spans); err == nil {
+			f.exported.Add(int64(len(spans)))
+			if idx != start && f.active.CompareAndSwap(int64(start), int64(idx)) {
+				f.logger.WithFields(logrus.Fields{
+					"from_endpoint": f.endpoints[start],
+					"endpoint":      f.endpoints[idx],
+				}).Warn("Failed over to another OTLP endpoint")
+			}
+			return nil
+		}
+		if len(f.exporters) > 1 {
+			f.logger.WithError(err).WithField("endpoint", f.endpoints[idx]).Warn("OTLP export failed")
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return err
+}
+
+// Shutdown implements sdktrace.SpanExporter
+func (f *failoverExporter) Shutdown(ctx context.Context) error {
+	var errs []error
+	for _, exporter := range f.exporters {
+		errs = append(errs, exporter.Shutdown(ctx))
+	}
+	return errors.Join(errs...)
+}
@@ -1,11 +1,16 @@
 package middleware
 
 import (
+	"fmt"
+	"io"
 	"os"
+	"runtime/debug"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -32,25 +37,54 @@ func InitLogger() *logrus.Logger {
 	return logger
 }
 
+// RedirectGinOutput sends gin's own output, such as warnings and debug
+// messages, through logger so the log stream stays structured JSON with no
+// plain-text lines in between. The returned function closes the writers.
+func RedirectGinOutput(logger *logrus.Logger) func() {
+	entry := logger.WithField("source", "gin")
+	out := entry.WriterLevel(logrus.InfoLevel)
+	errOut := entry.WriterLevel(logrus.ErrorLevel)
+	gin.DefaultWriter = out
+	gin.DefaultErrorWriter = errOut
+	return func() {
+		out.Close()
+		errOut.Close()
+	}
+}
+
 // LoggingMiddleware creates a Gin middleware for structured logging with trace correlation
 func LoggingMiddleware(logger *logrus.Logger) gin.HandlerFunc {
-	return gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
+	// The formatter logs through logrus itself; its output is discarded so
+	// requests are not logged a second time via gin.DefaultWriter
+	return gin.LoggerWithConfig(gin.LoggerConfig{Output: io.Discard, Formatter: func(param gin.LogFormatterParams) string {
 		// Extract trace information from context
 		spanCtx := trace.SpanContextFromContext(param.Request.Context())
 		
 		fields := logrus.Fields{
-			"method":      param.Method,
-			"path":        param.Path,
-			"status_code": param.StatusCode,
-			"latency":     param.Latency.String(),
-			"client_ip":   param.ClientIP,
-			"user_agent":  param.Request.UserAgent(),
+			"method":         param.Method,
+			"path":           param.Path,
+			"status_code":    param.StatusCode,
+			"latency":        param.Latency.String(),
+			"client_ip":      param.ClientIP,
+			"user_agent":     param.Request.UserAgent(),
+			"response_bytes": max(param.BodySize, 0),
+		}
+		
+		// Set by BodySizes, absent when the request never reached it
+		if requestBytes, ok := param.Keys[requestBytesKey]; ok {
+			fields["request_bytes"] = requestBytes
+		}
+		
+		// Set by ScenarioBaggage for requests of a load generator scenario
+		if scenarioID, ok := param.Keys[ScenarioKey]; ok {
+			fields["scenario_id"] = scenarioID
 		}
 		
 		// Add trace information if available
 		if spanCtx.IsValid() {
 			fields["trace_id"] = spanCtx.TraceID().String()
 			fields["span_id"] = spanCtx.SpanID().String()
+			fields["trace_sampled"] = spanCtx.IsSampled()
 		}
 		
 		// Add error information if present
@@ -69,12 +103,14 @@ func LoggingMiddleware(logger *logrus.Logger) gin.HandlerFunc {
 		
 		// Return empty string since we're handling logging ourselves
 		return ""
-	})
+	}})
 }
 
 // RecoveryMiddleware creates a Gin middleware for panic recovery with logging
 func RecoveryMiddleware(logger *logrus.Logger) gin.HandlerFunc {
-	return gin.RecoveryWithWriter(os.Stdout, func(c *gin.Context, recovered interface{}) {
+	// gin's plain-text panic report is discarded, the stack trace goes into
+	// the structured log entry instead
+	return gin.RecoveryWithWriter(io.Discard, func(c *gin.Context, recovered interface{}) {
 		// Extract trace information
 		spanCtx := trace.SpanContextFromContext(c.Request.Context())
 		
@@ -91,6 +127,25 @@ func RecoveryMiddleware(logger *logrus.Logger) gin.HandlerFunc {
 			fields["span_id"] = spanCtx.SpanID().String()
 		}
 		
+		// Attach the panic to the active span so crashes are visible in traces.
+		// This needs the span to still be open, i.e. this middleware must run
+		// inside the OpenTelemetry middleware.
+		exceptionType := fmt.Sprintf("%T", recovered)
+		exceptionMessage := fmt.Sprint(recovered)
+		stacktrace := string(debug.Stack())
+
+		span := trace.SpanFromContext(c.Request.Context())
+		exceptionAttrs := []attribute.KeyValue{
+			attribute.String("exception.type", exceptionType),
+			attribute.String("exception.message", exceptionMessage),
+			attribute.String("exception.stacktrace", stacktrace),
+		}
+		span.AddEvent("exception", trace.WithAttributes(exceptionAttrs...))
+		span.SetAttributes(exceptionAttrs...)
+		span.SetStatus(codes.Error, "panic: "+exceptionMessage)
+
+		fields["exception_type"] = exceptionType
+		fields["stacktrace"] = stacktrace
 		logger.WithFields(fields).Error("Panic recovered in HTTP handler")
 		
 		c.AbortWithStatus(500)
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"os"
+	"os/signal"
+
+	"github.com/sirupsen/logrus"
+)
+
+// logLevelCycle is the order SIGUSR1 steps through, wrapping back to info
+var logLevelCycle = []logrus.Level{logrus.InfoLevel, logrus.DebugLevel, logrus.TraceLevel}
+
+// CycleLogLevel moves the logger to the next level in info→debug→trace→info
+// and returns the new level. Levels outside the cycle go back to info.
+func CycleLogLevel(logger *logrus.Logger) logrus.Level {
+	next := logrus.InfoLevel
+	current := logger.GetLevel()
+	for i, level := range logLevelCycle {
+		if level == current {
+			next = logLevelCycle[(i+1)%len(logLevelCycle)]
+			break
+		}
+	}
+	logger.SetLevel(next)
+	return next
+}
+
+// CycleLogLevelOn cycles the log level every time sig is received, so debug
+// logs can be switched on during an incident and off again without a restart
+func CycleLogLevelOn(logger *logrus.Logger, sig os.Signal) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, sig)
+	go func() {
+		for range ch {
+			from := logger.GetLevel()
+			to := CycleLogLevel(logger)
+			// Logged at warn so the change is visible whatever the new level
+			logger.WithFields(logrus.Fields{
+				"from": from.String(),
+				"to":   to.String(),
+			}).Warn("Log level changed")
+		}
+	}()
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// LoadShedding rejects requests with 503 and a Retry-After header once more
+// than limit requests are in flight, instead of letting them queue up.
+// Exempt paths such as health probes are always served and not counted.
+// A limit of zero or less disables shedding.
+func LoadShedding(limit int, retryAfter time.Duration, exempt ...string) gin.HandlerFunc {
+	if limit <= 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	exemptPaths := make(map[string]bool, len(exempt))
+	for _, path := range exempt {
+		exemptPaths[path] = true
+	}
+	retryAfterSeconds := strconv.Itoa(int((retryAfter + time.Second - 1) / time.Second))
+
+	var inFlight atomic.Int64
+	return func(c *gin.Context) {
+		if exemptPaths[c.Request.URL.Path] {
+			c.Next()
+			return
+		}
+
+		span := trace.SpanFromContext(c.Request.Context())
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+
+		if current > int64(limit) {
+			span.SetAttributes(
+				attribute.Bool("overload", true),
+				attribute.Int64("overload.in_flight", current-1),
+				attribute.Int("overload.limit", limit),
+			)
+			c.Header("Retry-After", retryAfterSeconds)
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+				"error": "Server overloaded, retry later",
+			})
+			return
+		}
+
+		span.SetAttributes(attribute.Bool("overload", false))
+		c.Next()
+	}
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/contrib/propagators/b3"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// DefaultPropagators are used when OTEL_PROPAGATORS is unset
+var DefaultPropagators = []string{"tracecontext", "baggage"}
+
+// NewPropagator composes the named propagators in order, using the names of
+// the OTEL_PROPAGATORS specification: tracecontext, baggage, b3 (single
+// header) and b3multi (X-B3-* headers). Extraction accepts either B3 form;
+// the name only picks how context is injected into outgoing requests.
+func NewPropagator(names []string) (propagation.TextMapPropagator, error) {
+	if len(names) == 0 {
+		names = DefaultPropagators
+	}
+	propagators := make([]propagation.TextMapPropagator, 0, len(names))
+	for _, name := range names {
+		switch strings.TrimSpace(name) {
+		case "tracecontext":
+			propagators = append(propagators, propagation.TraceContext{})
+		case "baggage":
+			propagators = append(propagators, propagation.Baggage{})
+		case "b3":
+			propagators = append(propagators, b3.New(b3.WithInjectEncoding(b3.B3SingleHeader)))
+		case "b3multi":
+			propagators = append(propagators, b3.New(b3.WithInjectEncoding(b3.B3MultipleHeader)))
+		default:
+			return nil, fmt.Errorf("unknown propagator %q, expected tracecontext, baggage, b3 or b3multi", name)
+		}
+	}
+	return propagation.NewCompositeTextMapPropagator(propagators...), nil
+}
@@ -0,0 +1,105 @@
+package middleware_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/misua/eks-with-otel/demo-app/internal/middleware"
+	"github.com/misua/eks-with-otel/demo-app/internal/server"
+	"github.com/misua/eks-with-otel/demo-app/internal/storage"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+const (
+	incomingTraceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	incomingSpanID  = "00f067aa0ba902b7"
+)
+
+func TestPropagatorsContinueIncomingTrace(t *testing.T) {
+	tests := []struct {
+		name         string
+		propagators  []string
+		headers      map[string]string
+		wantContinue bool
+	}{
+		{"b3 single header", []string{"tracecontext", "baggage", "b3"}, map[string]string{
+			"b3": incomingTraceID + "-" + incomingSpanID + "-1",
+		}, true},
+		{"b3 multi header", []string{"tracecontext", "b3multi"}, map[string]string{
+			"X-B3-TraceId": incomingTraceID,
+			"X-B3-SpanId":  incomingSpanID,
+			"X-B3-Sampled": "1",
+		}, true},
+		{"b3 single header accepted by b3multi", []string{"b3multi"}, map[string]string{
+			"b3": incomingTraceID + "-" + incomingSpanID + "-1",
+		}, true},
+		{"tracecontext", nil, map[string]string{
+			"traceparent": "00-" + incomingTraceID + "-" + incomingSpanID + "-01",
+		}, true},
+		{"b3 without the b3 propagator", nil, map[string]string{
+			"b3": incomingTraceID + "-" + incomingSpanID + "-1",
+		}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			propagator, err := middleware.NewPropagator(tt.propagators)
+			if err != nil {
+				t.Fatalf("NewPropagator: %v", err)
+			}
+			previousPropagator, previousProvider := otel.GetTextMapPropagator(), otel.GetTracerProvider()
+			recorder := tracetest.NewSpanRecorder()
+			otel.SetTextMapPropagator(propagator)
+			otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+			t.Cleanup(func() {
+				otel.SetTextMapPropagator(previousPropagator)
+				otel.SetTracerProvider(previousProvider)
+			})
+
+			srv := newRouterServer(t)
+			req, _ := http.NewRequest(http.MethodGet, srv.URL+"/health", nil)
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+			resp, err := srv.Client().Do(req)
+			if err != nil {
+				t.Fatalf("request: %v", err)
+			}
+			resp.Body.Close()
+
+			var serverSpan sdktrace.ReadOnlySpan
+			for _, span := range recorder.Ended() {
+				if span.Name() == "/health" || span.Name() == "GET /health" {
+					serverSpan = span
+				}
+			}
+			if serverSpan == nil {
+				t.Fatalf("no request span among %d ended spans", len(recorder.Ended()))
+			}
+
+			continued := serverSpan.SpanContext().TraceID().String() == incomingTraceID &&
+				serverSpan.Parent().SpanID().String() == incomingSpanID
+			if continued != tt.wantContinue {
+				t.Errorf("trace continued = %v, want %v (trace %s, parent %s)", continued, tt.wantContinue,
+					serverSpan.SpanContext().TraceID(), serverSpan.Parent().SpanID())
+			}
+		})
+	}
+}
+
+// newRouterServer serves the full router on top of a fresh memory store
+func newRouterServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	srv := httptest.NewServer(server.NewRouter(storage.NewMemoryStorage(), logger, server.RouterOptions{}))
+	t.Cleanup(srv.Close)
+	return srv
+}
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ConcurrencyLimit lets at most limit requests run their handlers at once.
+// Unlike LoadShedding, requests beyond the limit queue for a free slot, for
+// up to timeout or until the client goes away, and get a 503 when none frees
+// up in time. The wait is recorded as queue_wait_ms on the request span and
+// in the http.request.queue_wait histogram. Exempt paths skip the queue.
+// A limit of zero or less disables it.
+func ConcurrencyLimit(limit int, timeout time.Duration, exempt ...string) gin.HandlerFunc {
+	if limit <= 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	exemptPaths := make(map[string]bool, len(exempt))
+	for _, path := range exempt {
+		exemptPaths[path] = true
+	}
+
+	queueWait, _ := otel.Meter("http").Float64Histogram(
+		"http.request.queue_wait",
+		metric.WithDescription("Time requests waited for a free handler slot"),
+		metric.WithUnit("ms"),
+	)
+
+	slots := make(chan struct{}, limit)
+	return func(c *gin.Context) {
+		if exemptPaths[c.Request.URL.Path] {
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+		span := trace.SpanFromContext(ctx)
+		start := time.Now()
+
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+
+		acquired := false
+		select {
+		case slots <- struct{}{}:
+			acquired = true
+		case <-timer.C:
+		case <-ctx.Done():
+		}
+
+		waited := float64(time.Since(start).Microseconds()) / 1000
+		queueWait.Record(ctx, waited, metric.WithAttributes(attribute.Bool("queue.acquired", acquired)))
+		span.SetAttributes(
+			attribute.Float64("queue_wait_ms", waited),
+			attribute.Bool("queue.timed_out", !acquired),
+		)
+
+		if !acquired {
+			span.SetAttributes(attribute.Int("queue.limit", limit))
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+				"error": "Server busy, no handler slot freed up in time",
+			})
+			return
+		}
+		defer func() { <-slots }()
+
+		c.Next()
+	}
+}
@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/metric"
+)
+
+// runtimeStatsInterval is how often runtime.ReadMemStats runs. It briefly
+// stops the world, so collection does not call it on every export.
+const runtimeStatsInterval = 10 * time.Second
+
+// runtimeStats caches the last runtime.ReadMemStats result for the callbacks
+type runtimeStats struct {
+	mu         sync.Mutex
+	heapAlloc  uint64
+	numGC      uint32
+	pauseTotal time.Duration
+	lastPause  time.Duration
+}
+
+func (s *runtimeStats) read() {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.heapAlloc = m.HeapAlloc
+	s.numGC = m.NumGC
+	s.pauseTotal = time.Duration(m.PauseTotalNs)
+	if m.NumGC > 0 {
+		s.lastPause = time.Duration(m.PauseNs[(m.NumGC+255)%256])
+	}
+}
+
+// startRuntimeMetrics registers goroutine, heap and GC pause instruments on
+// meter and refreshes the memory stats every runtimeStatsInterval until the
+// returned function is called
+func startRuntimeMetrics(meter metric.Meter) (func(), error) {
+	stats := &runtimeStats{}
+	stats.read()
+
+	goroutines, err := meter.Int64ObservableGauge("runtime.goroutines",
+		metric.WithDescription("Number of live goroutines"),
+		metric.WithUnit("{goroutine}"))
+	if err != nil {
+		return nil, err
+	}
+	heapAlloc, err := meter.Int64ObservableGauge("runtime.heap_alloc",
+		metric.WithDescription("Bytes of allocated heap objects"),
+		metric.WithUnit("By"))
+	if err != nil {
+		return nil, err
+	}
+	gcCount, err := meter.Int64ObservableCounter("runtime.gc.count",
+		metric.WithDescription("Number of completed GC cycles"),
+		metric.WithUnit("{cycle}"))
+	if err != nil {
+		return nil, err
+	}
+	gcPauseTotal, err := meter.Float64ObservableCounter("runtime.gc.pause_total",
+		metric.WithDescription("Cumulative stop-the-world GC pause time"),
+		metric.WithUnit("ms"))
+	if err != nil {
+		return nil, err
+	}
+	gcLastPause, err := meter.Float64ObservableGauge("runtime.gc.last_pause",
+		metric.WithDescription("Duration of the most recent GC pause"),
+		metric.WithUnit("ms"))
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		o.ObserveInt64(goroutines, int64(runtime.NumGoroutine()))
+
+		stats.mu.Lock()
+		defer stats.mu.Unlock()
+		o.ObserveInt64(heapAlloc, int64(stats.heapAlloc))
+		o.ObserveInt64(gcCount, int64(stats.numGC))
+		o.ObserveFloat64(gcPauseTotal, float64(stats.pauseTotal)/float64(time.Millisecond))
+		o.ObserveFloat64(gcLastPause, float64(stats.lastPause)/float64(time.Millisecond))
+		return nil
+	}, goroutines, heapAlloc, gcCount, gcPauseTotal, gcLastPause)
+	if err != nil {
+		return nil, err
+	}
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(runtimeStatsInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				stats.read()
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(stop)
+		<-done
+	}, nil
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DefaultScenarioBaggageKey is the baggage member the load generator's
+// scenario mode sets on every request of one scenario run
+const DefaultScenarioBaggageKey = "scenario.id"
+
+// ScenarioKey is the gin context key under which ScenarioBaggage leaves the
+// scenario ID for LoggingMiddleware and the handler spans
+const ScenarioKey = "scenario_id"
+
+// ScenarioBaggage copies the scenario ID from the baggage member named key
+// onto the request span as scenario.id and into the gin context, so every
+// span and access log line of a scenario run can be found by one ID. It must
+// run inside the OpenTelemetry middleware, which extracts the baggage.
+func ScenarioBaggage(key string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if id := baggage.FromContext(c.Request.Context()).Member(key).Value(); id != "" {
+			trace.SpanFromContext(c.Request.Context()).SetAttributes(attribute.String("scenario.id", id))
+			c.Set(ScenarioKey, id)
+		}
+		c.Next()
+	}
+}
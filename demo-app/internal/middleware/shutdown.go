@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DrainingHeader is set on responses written while the server shuts down
+const DrainingHeader = "X-Server-Draining"
+
+// errShutdownAborted is attached to requests cut off at the end of the drain
+// window; the OpenTelemetry middleware turns it into the span status
+var errShutdownAborted = errors.New("aborted due to shutdown")
+
+// Drain coordinates graceful shutdown with the requests still in flight.
+// Its BaseContext must be installed on the http.Server so that Abort cancels
+// every request context.
+type Drain struct {
+	draining atomic.Bool
+	inFlight atomic.Int64
+	ctx      context.Context
+	abort    context.CancelFunc
+}
+
+// NewDrain creates a Drain in the serving state
+func NewDrain() *Drain {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Drain{ctx: ctx, abort: cancel}
+}
+
+// BaseContext is meant for http.Server.BaseContext
+func (d *Drain) BaseContext(net.Listener) context.Context {
+	return d.ctx
+}
+
+// Start marks the beginning of the drain window
+func (d *Drain) Start() {
+	d.draining.Store(true)
+}
+
+// Abort cancels the requests that did not finish within the drain window and
+// waits up to wait for them to write their responses and end their spans.
+// It returns the number of requests that were aborted.
+func (d *Drain) Abort(wait time.Duration) int64 {
+	aborted := d.inFlight.Load()
+	d.abort()
+
+	deadline := time.Now().Add(wait)
+	for d.inFlight.Load() > 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	return aborted
+}
+
+// Middleware tags responses written during the drain window with
+// DrainingHeader and Connection: close, and marks requests cancelled by Abort
+// on their span. It must run inside the OpenTelemetry middleware.
+func (d *Drain) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		d.inFlight.Add(1)
+		defer d.inFlight.Add(-1)
+
+		c.Writer = &drainingWriter{ResponseWriter: c.Writer, drain: d}
+		c.Next()
+
+		if d.ctx.Err() == nil {
+			return
+		}
+		span := trace.SpanFromContext(c.Request.Context())
+		span.SetAttributes(attribute.Bool("shutdown.aborted", true))
+		span.SetStatus(codes.Error, errShutdownAborted.Error())
+		_ = c.Error(errShutdownAborted)
+		if !c.Writer.Written() {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+				"error": "Server is shutting down, retry the request",
+			})
+		}
+	}
+}
+
+// drainingWriter adds the draining headers at the moment the response header
+// is written, so requests already in flight when shutdown starts get them too
+type drainingWriter struct {
+	gin.ResponseWriter
+	drain *Drain
+}
+
+func (w *drainingWriter) WriteHeader(code int) {
+	w.tagDraining()
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *drainingWriter) WriteHeaderNow() {
+	w.tagDraining()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *drainingWriter) Write(data []byte) (int, error) {
+	w.tagDraining()
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *drainingWriter) WriteString(s string) (int, error) {
+	w.tagDraining()
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *drainingWriter) tagDraining() {
+	if w.drain.draining.Load() && !w.ResponseWriter.Written() {
+		w.Header().Set(DrainingHeader, "true")
+		w.Header().Set("Connection", "close")
+	}
+}
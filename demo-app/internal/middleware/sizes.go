@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"io"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// requestBytesKey is the gin context key under which BodySizes leaves the
+// request size for LoggingMiddleware
+const requestBytesKey = "request_bytes"
+
+// countingReader counts the bytes read from a request body
+type countingReader struct {
+	io.ReadCloser
+	n atomic.Int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.n.Add(int64(n))
+	return n, err
+}
+
+// BodySizes records the request and response body sizes as the request.bytes
+// and response.bytes span attributes and hands the request size to
+// LoggingMiddleware. The request size is the Content-Length when the client
+// sent one and otherwise, e.g. for chunked uploads, the bytes the handler
+// actually read. It must run inside the OpenTelemetry middleware and before
+// any middleware that rewrites the body, so wire sizes are measured.
+func BodySizes() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		contentLength := c.Request.ContentLength
+		var body *countingReader
+		if c.Request.Body != nil {
+			body = &countingReader{ReadCloser: c.Request.Body}
+			c.Request.Body = body
+		}
+
+		c.Next()
+
+		requestBytes := contentLength
+		if requestBytes < 0 {
+			requestBytes = 0
+			if body != nil {
+				requestBytes = body.n.Load()
+			}
+		}
+		c.Set(requestBytesKey, requestBytes)
+
+		// gin reports -1 until something has been written
+		responseBytes := max(c.Writer.Size(), 0)
+		trace.SpanFromContext(c.Request.Context()).SetAttributes(
+			attribute.Int64("request.bytes", requestBytes),
+			attribute.Int("response.bytes", responseBytes),
+		)
+	}
+}
@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SLOBudgets maps a route, optionally prefixed with a method as in
+// "GET /api/v1/items/:id", to its response time budget
+type SLOBudgets map[string]time.Duration
+
+// ParseSLOBudgets parses SLO_BUDGETS entries of the form ROUTE=DURATION or
+// METHOD ROUTE=DURATION, where ROUTE is a gin template such as
+// /api/v1/items/:id
+func ParseSLOBudgets(entries []string) (SLOBudgets, error) {
+	budgets := make(SLOBudgets, len(entries))
+	for _, entry := range entries {
+		// Cut at the last = so routes are never split
+		i := strings.LastIndex(entry, "=")
+		if i < 0 {
+			return nil, fmt.Errorf("budget %q must be ROUTE=DURATION", entry)
+		}
+		route, value := strings.Join(strings.Fields(entry[:i]), " "), strings.TrimSpace(entry[i+1:])
+		if route == "" {
+			return nil, fmt.Errorf("budget %q has no route", entry)
+		}
+		budget, err := time.ParseDuration(value)
+		if err != nil || budget <= 0 {
+			return nil, fmt.Errorf("budget %q must have a positive duration", entry)
+		}
+		budgets[route] = budget
+	}
+	return budgets, nil
+}
+
+// budgetFor returns the budget of a request, a method specific entry winning
+// over one for the route alone
+func (b SLOBudgets) budgetFor(method, route string) (time.Duration, bool) {
+	if budget, ok := b[method+" "+route]; ok {
+		return budget, true
+	}
+	budget, ok := b[route]
+	return budget, ok
+}
+
+// SLOBudget compares every request to a route with a budget against it,
+// recording slo.met and slo.budget_ms on the request span and counting misses
+// in http.slo.violations by route. Requests to routes without a budget are
+// left alone, and no budgets disables the middleware. It must run inside the
+// OpenTelemetry middleware and as early as possible so the measured time is
+// close to what the client sees.
+func SLOBudget(budgets SLOBudgets) gin.HandlerFunc {
+	if len(budgets) == 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	violations, _ := otel.Meter("http").Int64Counter(
+		"http.slo.violations",
+		metric.WithDescription("HTTP requests that took longer than their route's response time budget"),
+	)
+
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		budget, ok := budgets.budgetFor(c.Request.Method, route)
+		if route == "" || !ok {
+			return
+		}
+
+		met := time.Since(start) <= budget
+		trace.SpanFromContext(c.Request.Context()).SetAttributes(
+			attribute.Bool("slo.met", met),
+			attribute.Int64("slo.budget_ms", budget.Milliseconds()),
+		)
+		if !met {
+			violations.Add(c.Request.Context(), 1,
+				metric.WithAttributes(
+					attribute.String("http.method", c.Request.Method),
+					attribute.String("http.route", route),
+				),
+			)
+		}
+	}
+}
@@ -2,66 +2,322 @@ package middleware
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/sirupsen/logrus"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
-	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
 )
 
-// InitTracer initializes OpenTelemetry tracing
-func InitTracer(serviceName, serviceVersion, otlpEndpoint string) (func(), error) {
-	// Create OTLP HTTP exporter
-	exporter, err := otlptracehttp.New(
-		context.Background(),
-		otlptracehttp.WithEndpoint(otlpEndpoint),
-		otlptracehttp.WithInsecure(), // Use insecure connection for demo
-		otlptracehttp.WithURLPath("/v1/traces"), // Explicitly set the path
-	)
+// probeTimeout bounds the startup reachability check of the collector
+const probeTimeout = 2 * time.Second
+
+// TracerOptions configures InitTracer
+type TracerOptions struct {
+	// Required makes an unreachable collector a startup error instead of a warning
+	Required bool
+	// RetryInterval is how often to retry a collector that was unreachable at
+	// startup. Zero disables background retries.
+	RetryInterval time.Duration
+	// Logger receives warnings about the exporter state
+	Logger *logrus.Logger
+	// Batch tunes the batch span processor
+	Batch BatchOptions
+	// Propagators names the trace context formats read from and written to
+	// requests, DefaultPropagators when empty
+	Propagators []string
+}
+
+// BatchOptions tunes the batch span processor, trading memory for throughput.
+// Zero values keep the SDK defaults.
+type BatchOptions struct {
+	MaxQueueSize       int
+	MaxExportBatchSize int
+	ExportTimeout      time.Duration
+	ScheduleDelay      time.Duration
+}
+
+// processorOptions converts the non-zero settings into SDK options
+func (b BatchOptions) processorOptions() []sdktrace.BatchSpanProcessorOption {
+	var opts []sdktrace.BatchSpanProcessorOption
+	if b.MaxQueueSize > 0 {
+		opts = append(opts, sdktrace.WithMaxQueueSize(b.MaxQueueSize))
+	}
+	if b.MaxExportBatchSize > 0 {
+		opts = append(opts, sdktrace.WithMaxExportBatchSize(b.MaxExportBatchSize))
+	}
+	if b.ExportTimeout > 0 {
+		opts = append(opts, sdktrace.WithExportTimeout(b.ExportTimeout))
+	}
+	if b.ScheduleDelay > 0 {
+		opts = append(opts, sdktrace.WithBatchTimeout(b.ScheduleDelay))
+	}
+	return opts
+}
+
+// InitTracer initializes OpenTelemetry tracing. otlpEndpoint may list several
+// collectors separated by commas; spans go to the first reachable one and fail
+// over to the next when an export fails. When no collector can be reached
+// tracing falls back to a no-op provider so the app keeps serving traffic,
+// unless opts.Required is set.
+func InitTracer(serviceName, serviceVersion, otlpEndpoint string, opts TracerOptions) (*Tracing, error) {
+	endpoints := endpointHosts(otlpEndpoint)
+
+	// Create resource with service information
+	res, err := newResource(serviceName, serviceVersion)
 	if err != nil {
 		return nil, err
 	}
 
-	// Create resource with service information
-	res, err := resource.New(
-		context.Background(),
-		resource.WithAttributes(
-			semconv.ServiceNameKey.String(serviceName),
-			semconv.ServiceVersionKey.String(serviceVersion),
-			semconv.DeploymentEnvironmentKey.String("development"),
-		),
-	)
+	// Set global propagator for distributed tracing. This is done even without
+	// an exporter so incoming trace context is still passed along.
+	if len(opts.Propagators) == 0 {
+		opts.Propagators = DefaultPropagators
+	}
+	propagator, err := NewPropagator(opts.Propagators)
 	if err != nil {
 		return nil, err
 	}
+	otel.SetTextMapPropagator(propagator)
+	opts.Logger.WithField("propagators", opts.Propagators).Info("Trace context propagation configured")
+
+	opts.Logger.WithFields(logrus.Fields{
+		"max_queue_size":        opts.Batch.MaxQueueSize,
+		"max_export_batch_size": opts.Batch.MaxExportBatchSize,
+		"export_timeout":        opts.Batch.ExportTimeout.String(),
+		"schedule_delay":        opts.Batch.ScheduleDelay.String(),
+	}).Info("Batch span processor settings")
+
+	t := &Tracing{batch: opts.Batch, logger: opts.Logger}
+	active, err := probeEndpoints(endpoints)
+	if err != nil {
+		if opts.Required {
+			return nil, fmt.Errorf("OTLP endpoints %s unreachable: %w", strings.Join(endpoints, ","), err)
+		}
+
+		// Leaving the global provider unset keeps it a no-op that later
+		// delegates to the real provider once the retry succeeds
+		opts.Logger.WithError(err).WithField("endpoints", endpoints).
+			Warn("OTLP endpoint unreachable, tracing disabled")
+		if opts.RetryInterval > 0 {
+			t.retry(endpoints, res, opts)
+		}
+		return t, nil
+	}
+
+	if err := t.install(endpoints, active, res); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// ErrTracingDisabled is returned by ForceFlush while no collector has been reached
+var ErrTracingDisabled = errors.New("tracing disabled")
+
+// Tracing owns the tracer provider once it has been installed and the
+// background retry loop while it has not
+type Tracing struct {
+	batch  BatchOptions
+	logger *logrus.Logger
+
+	mu       sync.Mutex
+	tp       *sdktrace.TracerProvider
+	exporter *failoverExporter
+	stop     chan struct{}
+	stopOnce sync.Once
+	// done is closed once the retry loop has exited
+	done chan struct{}
+}
+
+// install creates the exporter and provider and sets it as the global
+// provider, starting with the endpoint at index active
+func (t *Tracing) install(endpoints []string, active int, res *resource.Resource) error {
+	exporters := make([]sdktrace.SpanExporter, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		// Create OTLP HTTP exporter
+		exporter, err := otlptracehttp.New(
+			context.Background(),
+			otlptracehttp.WithEndpoint(endpoint),
+			otlptracehttp.WithInsecure(), // Use insecure connection for demo
+			otlptracehttp.WithURLPath("/v1/traces"), // Explicitly set the path
+			// With a fallback collector, failing over beats retrying the same one
+			otlptracehttp.WithRetry(otlptracehttp.RetryConfig{Enabled: len(endpoints) == 1}),
+		)
+		if err != nil {
+			return err
+		}
+		exporters = append(exporters, exporter)
+	}
 
 	// Create trace provider
+	exporter := newFailoverExporter(endpoints, exporters, active, t.logger)
 	tp := sdktrace.NewTracerProvider(
-		sdktrace.WithBatcher(exporter),
+		sdktrace.WithBatcher(exporter, t.batch.processorOptions()...),
 		sdktrace.WithResource(res),
 		sdktrace.WithSampler(sdktrace.AlwaysSample()), // Sample all traces for demo
 	)
 
+	t.mu.Lock()
+	t.tp = tp
+	t.exporter = exporter
+	t.mu.Unlock()
+
 	// Set global trace provider
 	otel.SetTracerProvider(tp)
+	t.logger.WithField("endpoint", endpoints[active]).Info("Exporting traces to OTLP endpoint")
+	return nil
+}
 
-	// Set global propagator for distributed tracing
-	otel.SetTextMapPropagator(
-		propagation.NewCompositeTextMapPropagator(
-			propagation.TraceContext{},
-			propagation.Baggage{},
-		),
-	)
+// retry probes the endpoints in the background and installs the provider as
+// soon as one of the collectors becomes reachable
+func (t *Tracing) retry(endpoints []string, res *resource.Resource, opts TracerOptions) {
+	t.stop = make(chan struct{})
+	t.done = make(chan struct{})
+
+	go func() {
+		defer close(t.done)
+		ticker := time.NewTicker(opts.RetryInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-t.stop:
+				return
+			case <-ticker.C:
+				active, err := probeEndpoints(endpoints)
+				if err != nil {
+					continue
+				}
+				if err := t.install(endpoints, active, res); err != nil {
+					opts.Logger.WithError(err).WithField("endpoint", endpoints[active]).
+						Error("Failed to initialize OTLP exporter after collector became reachable")
+					return
+				}
+				opts.Logger.WithField("endpoint", endpoints[active]).Info("OTLP endpoint reachable, tracing enabled")
+				return
+			}
+		}
+	}()
+}
+
+// ForceFlush exports all queued spans right away instead of waiting for the
+// batch timer. It returns the number of spans exported while flushing, which
+// may include a batch the timer sent concurrently.
+func (t *Tracing) ForceFlush(ctx context.Context) (int, error) {
+	t.mu.Lock()
+	tp, exporter := t.tp, t.exporter
+	t.mu.Unlock()
+	if tp == nil {
+		return 0, ErrTracingDisabled
+	}
+
+	before := exporter.exported.Load()
+	err := tp.ForceFlush(ctx)
+	return int(exporter.exported.Load() - before), err
+}
+
+// Shutdown stops the retry loop and flushes the provider, if any. It waits
+// for the loop to exit first, as a probe in flight may still install one.
+func (t *Tracing) Shutdown() {
+	if t.stop != nil {
+		t.stopOnce.Do(func() { close(t.stop) })
+		<-t.done
+	}
+
+	t.mu.Lock()
+	tp := t.tp
+	t.mu.Unlock()
+	if tp == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := tp.Shutdown(ctx); err != nil {
+		// Log error but don't panic on shutdown
+	}
+}
+
+// endpointHosts splits a comma-separated list of OTLP endpoints into their
+// host:port forms, dropping empty entries
+func endpointHosts(endpoints string) []string {
+	var hosts []string
+	for _, endpoint := range strings.Split(endpoints, ",") {
+		if endpoint = strings.TrimSpace(endpoint); endpoint != "" {
+			hosts = append(hosts, endpointHost(endpoint))
+		}
+	}
+	if len(hosts) == 0 {
+		hosts = append(hosts, "")
+	}
+	return hosts
+}
 
-	// Return cleanup function
-	return func() {
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
-		if err := tp.Shutdown(ctx); err != nil {
-			// Log error but don't panic on shutdown
+// endpointHost turns an OTLP endpoint given either as a URL
+// (http://collector:4318) or as host:port into the host:port form the
+// exporters expect
+func endpointHost(endpoint string) string {
+	if strings.Contains(endpoint, "://") {
+		if u, err := url.Parse(endpoint); err == nil && u.Host != "" {
+			return u.Host
 		}
-	}, nil
+	}
+	return strings.TrimSuffix(endpoint, "/")
+}
+
+// probeEndpoint checks that something is listening on the collector address
+func probeEndpoint(endpoint string) error {
+	address := endpoint
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		address = net.JoinHostPort(address, "4318")
+	}
+	conn, err := net.DialTimeout("tcp", address, probeTimeout)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// resourceAttributes are added to the resource on top of the service identity
+var resourceAttributes []attribute.KeyValue
+
+// AddResourceAttributes records app configuration on the resource shared by
+// traces and metrics. It must be called before InitTracer and InitMeter.
+func AddResourceAttributes(attrs ...attribute.KeyValue) {
+	resourceAttributes = append(resourceAttributes, attrs...)
+}
+
+// probeEndpoints returns the index of the first reachable endpoint, or the
+// error of the last one tried when none are
+func probeEndpoints(endpoints []string) (int, error) {
+	var err error
+	for i, endpoint := range endpoints {
+		if err = probeEndpoint(endpoint); err == nil {
+			return i, nil
+		}
+	}
+	return 0, err
+}
+
+// newResource describes the service for both traces and metrics
+func newResource(serviceName, serviceVersion string) (*resource.Resource, error) {
+	return resource.New(
+		context.Background(),
+		resource.WithAttributes(
+			semconv.ServiceNameKey.String(serviceName),
+			semconv.ServiceVersionKey.String(serviceVersion),
+			semconv.DeploymentEnvironmentKey.String("development"),
+		),
+		resource.WithAttributes(resourceAttributes...),
+	)
 }
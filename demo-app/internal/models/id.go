@@ -0,0 +1,92 @@
+package models
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// IDStrategyUUID generates random UUIDv4 IDs
+	IDStrategyUUID = "uuid"
+	// IDStrategyULID generates lexicographically sortable ULIDs
+	IDStrategyULID = "ulid"
+	// IDStrategySequential generates 1, 2, 3, ... in creation order
+	IDStrategySequential = "sequential"
+)
+
+// IDGenerator returns a new unique item ID. Implementations must be safe for
+// concurrent use.
+type IDGenerator func() string
+
+var (
+	idStrategy              = IDStrategyUUID
+	idGenerator IDGenerator = newUUID
+
+	// sequence is the last ID handed out by the sequential strategy
+	sequence atomic.Uint64
+)
+
+// SetIDStrategy selects the generator used by NewItem. Unknown strategies are
+// treated as uuid, the default. It is meant to be called once at startup,
+// before serving traffic.
+func SetIDStrategy(strategy string) {
+	switch strategy {
+	case IDStrategyULID:
+		idStrategy, idGenerator = IDStrategyULID, newULID
+	case IDStrategySequential:
+		sequence.Store(0)
+		idStrategy, idGenerator = IDStrategySequential, func() string {
+			return strconv.FormatUint(sequence.Add(1), 10)
+		}
+	default:
+		idStrategy, idGenerator = IDStrategyUUID, newUUID
+	}
+}
+
+// AdvanceSequence moves the sequential strategy past n, so the next ID is at
+// least n+1. It never moves the sequence back. Stores call it after restoring
+// items, which would otherwise collide with IDs counted again from 1.
+func AdvanceSequence(n uint64) {
+	for {
+		current := sequence.Load()
+		if current >= n || sequence.CompareAndSwap(current, n) {
+			return
+		}
+	}
+}
+
+// IDStrategy returns the active ID strategy
+func IDStrategy() string {
+	return idStrategy
+}
+
+func newUUID() string {
+	return uuid.New().String()
+}
+
+// crockford is the ULID alphabet, which leaves out I, L, O and U
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// newULID builds a ULID from a 48-bit millisecond timestamp followed by 80
+// random bits, encoded as 26 Crockford base32 characters
+func newULID() string {
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[:8], uint64(time.Now().UnixMilli())<<16)
+	_, _ = rand.Read(b[6:])
+
+	// 128 bits in 26 characters: the first character carries the top 3 bits
+	hi := binary.BigEndian.Uint64(b[:8])
+	lo := binary.BigEndian.Uint64(b[8:])
+	var out [26]byte
+	for i := 25; i >= 0; i-- {
+		out[i] = crockford[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out[:])
+}
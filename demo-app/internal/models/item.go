@@ -1,39 +1,157 @@
 package models
 
 import (
+	"errors"
+	"fmt"
+	"strings"
 	"time"
-
-	"github.com/google/uuid"
+	"unicode/utf8"
 )
 
+// DefaultDescriptionMax is the default maximum description length in characters
+const DefaultDescriptionMax = 4096
+
+// ErrDescriptionTooLong is returned when a description exceeds the limit in reject mode
+var ErrDescriptionTooLong = errors.New("description too long")
+
+// DescriptionPolicy controls how over-long descriptions are handled
+type DescriptionPolicy struct {
+	// Max is the maximum description length in characters
+	Max int
+	// Truncate cuts descriptions to Max instead of rejecting them
+	Truncate bool
+}
+
+var descriptionPolicy = DescriptionPolicy{Max: DefaultDescriptionMax}
+
+// SetDescriptionPolicy sets the description limit applied by normalization.
+// It is meant to be called once at startup, before serving traffic.
+func SetDescriptionPolicy(policy DescriptionPolicy) {
+	descriptionPolicy = policy
+}
+
 // Item represents a simple item in our CRUD application
 type Item struct {
 	ID          string    `json:"id"`
 	Name        string    `json:"name" binding:"required"`
 	Description string    `json:"description"`
+	Owner       string    `json:"owner,omitempty"`
+	Flagged     bool      `json:"flagged"`
+	Version     int       `json:"version"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
+
+	// Metadata holds free-form demo fields such as price or quantity, stored
+	// and returned verbatim. It is replaced as a whole, never modified in place,
+	// so copies of an item may share it.
+	Metadata map[string]any `json:"metadata,omitempty"`
 }
 
 // NewItem creates a new item with generated ID and timestamps
 func NewItem(name, description string) *Item {
+	return NewItemWithID(idGenerator(), name, description)
+}
+
+// NewItemWithID creates a new item with a caller-chosen ID
+func NewItemWithID(id, name, description string) *Item {
 	now := time.Now()
 	return &Item{
-		ID:          uuid.New().String(),
-		Name:        name,
+		ID:          id,
+		Name:        TrimName(name),
 		Description: description,
+		Version:     1,
 		CreatedAt:   now,
 		UpdatedAt:   now,
 	}
 }
 
-// Update updates the item's fields and timestamp
+// Update updates the item's fields and timestamp. A name that is blank after
+// trimming leaves the name unchanged, like an empty one.
 func (i *Item) Update(name, description string) {
-	if name != "" {
+	if name = TrimName(name); name != "" {
 		i.Name = name
 	}
 	if description != "" {
 		i.Description = description
 	}
+	i.Version++
+	i.UpdatedAt = time.Now()
+}
+
+// SetFlagged marks or unmarks the item, bumping its version only when the
+// flag changes. It reports whether it did.
+func (i *Item) SetFlagged(flagged bool) bool {
+	if i.Flagged == flagged {
+		return false
+	}
+	i.Flagged = flagged
+	i.Version++
+	i.UpdatedAt = time.Now()
+	return true
+}
+
+// Touch bumps the version and timestamp without changing any field
+func (i *Item) Touch() {
+	i.Version++
+	i.UpdatedAt = time.Now()
+}
+
+// Replace overwrites the item's fields, unlike Update empty values included
+func (i *Item) Replace(name, description string, metadata map[string]any) {
+	i.Name = TrimName(name)
+	i.Description = description
+	i.Metadata = metadata
+	i.Version++
+	i.UpdatedAt = time.Now()
+}
+
+// TrimName strips leading and trailing whitespace from a name, keeping the
+// whitespace inside it, so a name of only spaces becomes empty and fails validation
+func TrimName(name string) string {
+	return strings.TrimSpace(name)
+}
+
+// Normalize applies the description policy to the item in place. It reports
+// whether the description was truncated.
+func (i *Item) Normalize() (truncated bool, err error) {
+	i.Description, truncated, err = NormalizeDescription(i.Description)
+	return truncated, err
+}
+
+// NormalizeDescription applies the description policy: descriptions within the
+// limit are returned unchanged, longer ones are either cut to the limit or
+// rejected with ErrDescriptionTooLong
+func NormalizeDescription(description string) (string, bool, error) {
+	max := descriptionPolicy.Max
+	if max <= 0 || utf8.RuneCountInString(description) <= max {
+		return description, false, nil
+	}
+	if !descriptionPolicy.Truncate {
+		return description, false, fmt.Errorf("%w: limit is %d characters", ErrDescriptionTooLong, max)
+	}
+	return string([]rune(description)[:max]), true, nil
+}
+
+// ItemPatch is a partial update: only non-nil fields are applied, so unlike
+// Update it can clear a description
+type ItemPatch struct {
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+}
+
+// IsEmpty reports whether the patch changes nothing
+func (p ItemPatch) IsEmpty() bool {
+	return p.Name == nil && p.Description == nil
+}
+
+// Apply applies the patch to the item and bumps its version
+func (i *Item) Apply(p ItemPatch) {
+	if p.Name != nil {
+		i.Name = TrimName(*p.Name)
+	}
+	if p.Description != nil {
+		i.Description = *p.Description
+	}
+	i.Version++
 	i.UpdatedAt = time.Now()
 }
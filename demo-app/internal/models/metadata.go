@@ -0,0 +1,70 @@
+package models
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// metadataKeys lists the metadata keys items may carry; empty allows any key
+var metadataKeys map[string]bool
+
+// SetMetadataKeys restricts item metadata to the given keys, an empty list
+// allows any key. It is meant to be called once at startup, before serving traffic.
+func SetMetadataKeys(keys []string) {
+	metadataKeys = nil
+	if len(keys) == 0 {
+		return
+	}
+	metadataKeys = make(map[string]bool, len(keys))
+	for _, key := range keys {
+		metadataKeys[key] = true
+	}
+}
+
+// validateMetadata reports every key not in the allowed list, in key order
+func validateMetadata(metadata map[string]any) []FieldError {
+	if metadataKeys == nil {
+		return nil
+	}
+	var errs []FieldError
+	for _, key := range sortedKeys(metadata) {
+		if !metadataKeys[key] {
+			errs = append(errs, FieldError{
+				Field:   "metadata." + key,
+				Rule:    RuleNotAllowed,
+				Message: fmt.Sprintf("metadata key %q is not allowed", key),
+			})
+		}
+	}
+	return errs
+}
+
+// MetadataMatches reports whether the item has every key of filter with a
+// value that prints the same, so ?metadata.quantity=3 matches the JSON number 3
+func (i *Item) MetadataMatches(filter map[string]string) bool {
+	for key, want := range filter {
+		value, ok := i.Metadata[key]
+		if !ok || fmt.Sprint(value) != want {
+			return false
+		}
+	}
+	return true
+}
+
+// MetadataEqual reports whether two metadata maps hold the same values
+func MetadataEqual(a, b map[string]any) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Timestamp formats for the JSON form of items, selected by TIME_FORMAT
+const (
+	// TimeFormatRFC3339 writes timestamps as RFC3339 strings
+	TimeFormatRFC3339 = "rfc3339"
+	// TimeFormatUnixMs writes timestamps as milliseconds since the Unix epoch
+	TimeFormatUnixMs = "unix_ms"
+)
+
+var unixMsTimes bool
+
+// SetTimeFormat selects how item timestamps are written as JSON. Unknown
+// formats are treated as RFC3339, the default. It is meant to be called once
+// at startup, before serving traffic.
+func SetTimeFormat(format string) {
+	unixMsTimes = format == TimeFormatUnixMs
+}
+
+// TimeFormat returns the active timestamp format
+func TimeFormat() string {
+	if unixMsTimes {
+		return TimeFormatUnixMs
+	}
+	return TimeFormatRFC3339
+}
+
+// itemFields has the fields of Item without its JSON methods
+type itemFields Item
+
+// MarshalJSON writes the item with timestamps in the active format. Epoch
+// milliseconds drop sub-millisecond precision.
+func (i Item) MarshalJSON() ([]byte, error) {
+	if !unixMsTimes {
+		return json.Marshal(itemFields(i))
+	}
+	return json.Marshal(struct {
+		itemFields
+		CreatedAt int64 `json:"created_at"`
+		UpdatedAt int64 `json:"updated_at"`
+	}{itemFields(i), i.CreatedAt.UnixMilli(), i.UpdatedAt.UnixMilli()})
+}
+
+// UnmarshalJSON reads an item whose timestamps are in either format, so
+// snapshots and imports work whatever TIME_FORMAT wrote them
+func (i *Item) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		itemFields
+		CreatedAt jsonTime `json:"created_at"`
+		UpdatedAt jsonTime `json:"updated_at"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*i = Item(raw.itemFields)
+	i.CreatedAt = time.Time(raw.CreatedAt)
+	i.UpdatedAt = time.Time(raw.UpdatedAt)
+	return nil
+}
+
+// jsonTime accepts an RFC3339 string or epoch milliseconds
+type jsonTime time.Time
+
+func (t *jsonTime) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var parsed time.Time
+		if err := json.Unmarshal(data, &parsed); err != nil {
+			return err
+		}
+		*t = jsonTime(parsed)
+		return nil
+	}
+	ms, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil {
+		return fmt.Errorf("timestamp %s is neither RFC3339 nor epoch milliseconds", data)
+	}
+	*t = jsonTime(time.UnixMilli(ms).UTC())
+	return nil
+}
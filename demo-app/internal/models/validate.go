@@ -0,0 +1,30 @@
+package models
+
+// Validation rules reported in FieldError.Rule
+const (
+	RuleRequired   = "required"
+	RuleTooLong    = "too_long"
+	RuleNotAllowed = "not_allowed"
+)
+
+// FieldError describes why a single field of an item is invalid
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Validate checks the item against the rules applied on create without
+// modifying it. It returns nil when the item is acceptable; a description
+// that the active policy would truncate counts as acceptable.
+func (i *Item) Validate() []FieldError {
+	var errs []FieldError
+	if TrimName(i.Name) == "" {
+		errs = append(errs, FieldError{Field: "name", Rule: RuleRequired, Message: "name is required"})
+	}
+	if _, _, err := NormalizeDescription(i.Description); err != nil {
+		errs = append(errs, FieldError{Field: "description", Rule: RuleTooLong, Message: err.Error()})
+	}
+	errs = append(errs, validateMetadata(i.Metadata)...)
+	return errs
+}
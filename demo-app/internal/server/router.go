@@ -0,0 +1,192 @@
+// Package server wires handlers and middleware into the API's HTTP router
+package server
+
+import (
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/misua/eks-with-otel/demo-app/internal/config"
+	"github.com/misua/eks-with-otel/demo-app/internal/handlers"
+	"github.com/misua/eks-with-otel/demo-app/internal/middleware"
+	"github.com/misua/eks-with-otel/demo-app/internal/storage"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+)
+
+// Service identifies the API in telemetry and on GET /
+const (
+	ServiceName    = "eks-otel-demo"
+	ServiceVersion = "1.0.0"
+)
+
+// probePaths skip load shedding and injected latency so probes and scrapes
+// keep answering under load
+var probePaths = []string{"/health", "/readyz", "/metrics"}
+
+// RouterOptions carries what main wires up around the router. The zero value
+// builds the full app with default configuration, reporting ready at once,
+// which is what integration tests need.
+type RouterOptions struct {
+	// Config defaults to config.Defaults()
+	Config *config.Config
+	// Readiness backs /readyz; nil reports ready immediately
+	Readiness *handlers.Readiness
+	// Drain tracks in-flight requests for shutdown; nil creates one
+	Drain *middleware.Drain
+	// Tracing is flushed by the admin flush-traces endpoint; nil reports
+	// tracing as disabled
+	Tracing handlers.TraceFlusher
+}
+
+// NewRouter builds the gin engine serving every route of the API on top of
+// store. Process-wide settings such as the ID strategy, description policy
+// and response format are applied by the caller beforehand.
+func NewRouter(store storage.Storage, logger *logrus.Logger, opts RouterOptions) *gin.Engine {
+	cfg := opts.Config
+	if cfg == nil {
+		cfg = config.Defaults()
+	}
+	readiness := opts.Readiness
+	if readiness == nil {
+		readiness = &handlers.Readiness{}
+		readiness.MarkReady()
+	}
+	drain := opts.Drain
+	if drain == nil {
+		drain = middleware.NewDrain()
+	}
+
+	// Artificial latency for demos, off unless INJECT_LATENCY is set.
+	// config.Load has already rejected unparsable values.
+	var latencyMin, latencyMax time.Duration
+	if cfg.InjectLatency != "" {
+		latencyMin, latencyMax, _ = middleware.ParseLatency(cfg.InjectLatency)
+		logger.WithFields(logrus.Fields{
+			"min": latencyMin.String(),
+			"max": latencyMax.String(),
+		}).Warn("Injecting artificial latency into every request")
+	}
+	if cfg.ChaosErrorRate > 0 {
+		logger.WithField("rate", cfg.ChaosErrorRate).Warn("Injecting synthetic 500 errors")
+	}
+
+	// API_BASE_PATH prefixes every route; probes and scrapes keep their
+	// plain paths unless API_BASE_PATH_PROBES is set, since kubelet and
+	// Prometheus usually reach the pod directly rather than via the ingress
+	probePrefix := ""
+	if cfg.BasePathProbes {
+		probePrefix = cfg.APIBasePath
+	}
+	unthrottledPaths := make([]string, len(probePaths))
+	for i, path := range probePaths {
+		unthrottledPaths[i] = probePrefix + path
+	}
+	if cfg.APIBasePath != "" {
+		logger.WithFields(logrus.Fields{
+			"base_path":     cfg.APIBasePath,
+			"probes_prefix": probePrefix,
+		}).Info("API base path configured")
+	}
+
+	// Initialize handlers
+	itemHandler := handlers.NewItemHandler(store, logger, handlers.ItemHandlerOptions{
+		IdempotencyTTL: cfg.IdempotencyTTL,
+		ListMaxUnpaged: cfg.ListMaxUnpaged,
+		ShallowHealth:  !cfg.HealthCheckDeep,
+	})
+	eventHandler := handlers.NewEventHandler(store, logger, cfg.SSEMaxSubscribers)
+	adminHandler := handlers.NewAdminHandler(store, opts.Tracing, logger, cfg.SnapshotPath)
+
+	// Create Gin router
+	router := gin.New()
+	router.HandleMethodNotAllowed = true
+
+	// Add middleware
+	router.Use(otelgin.Middleware(ServiceName)) // OpenTelemetry middleware
+	// Logging runs inside the OpenTelemetry middleware, which restores the
+	// original request context on the way out, so access logs carry the trace
+	router.Use(middleware.LoggingMiddleware(logger))
+	router.Use(drain.Middleware())
+	router.Use(middleware.MetricsMiddleware())
+	// Budgets are checked around everything but the request span and access
+	// log, injected latency included
+	sloBudgets, _ := middleware.ParseSLOBudgets(cfg.SLOBudgets)
+	router.Use(middleware.SLOBudget(sloBudgets))
+	router.Use(middleware.BodySizes())
+	router.Use(middleware.ScenarioBaggage(cfg.ScenarioBaggageKey))
+	// Recovery runs inside the OpenTelemetry middleware so panics are recorded on the still-open request span
+	router.Use(middleware.RecoveryMiddleware(logger))
+	// Event streams hold their request for as long as the subscriber stays
+	// connected; SSE_MAX_SUBSCRIBERS bounds them instead of the request limits
+	unlimitedPaths := append(slices.Clone(unthrottledPaths), cfg.APIBasePath+"/api/v1/items/events")
+	router.Use(middleware.LoadShedding(cfg.MaxInFlight, cfg.OverloadRetryAfter, unlimitedPaths...))
+	// Requests that were not shed wait here for one of the handler slots
+	router.Use(middleware.ConcurrencyLimit(cfg.MaxConcurrent, cfg.QueueTimeout, unlimitedPaths...))
+	router.Use(middleware.GzipRequests(int64(cfg.MaxDecompressedBytes)))
+	router.Use(middleware.InjectLatency(latencyMin, latencyMax, unthrottledPaths...))
+	router.Use(middleware.ChaosErrors(cfg.ChaosErrorRate, unthrottledPaths...))
+
+	// Add CORS middleware for development
+	router.Use(func(c *gin.Context) {
+		c.Header("Access-Control-Allow-Origin", "*")
+		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization, Content-Encoding, X-Tenant-ID, Idempotency-Key")
+
+		if c.Request.Method == "OPTIONS" {
+			c.AbortWithStatus(204)
+			return
+		}
+
+		c.Next()
+	})
+
+	// JSON responses for unknown routes and unsupported methods
+	router.NoRoute(handlers.NotFound(logger))
+	router.NoMethod(handlers.MethodNotAllowed(logger))
+
+	base := router.Group(cfg.APIBasePath)
+
+	// Health check endpoint
+	probes := router.Group(probePrefix)
+	probes.GET("/health", itemHandler.HealthCheck)
+	probes.GET("/readyz", readiness.Handler)
+	probes.GET("/metrics", middleware.MetricsHandler())
+	base.GET("/", handlers.ServiceInfo(ServiceName, ServiceVersion, cfg.APIBasePath+"/api/v1"))
+
+	// API routes
+	v1 := base.Group("/api/v1")
+	{
+		v1.GET("/items", itemHandler.GetItems)
+		v1.GET("/items/count", itemHandler.CountItems)
+		v1.GET("/items/events", eventHandler.StreamItemEvents)
+		v1.GET("/items/group-by", itemHandler.GroupItems)
+		v1.GET("/items/largest", itemHandler.GetLargestItems)
+		v1.GET("/items/:id", itemHandler.GetItem)
+		v1.GET("/items/:id/history", itemHandler.GetItemHistory)
+		v1.POST("/items/:id/flag", itemHandler.FlagItem)
+		v1.POST("/items/:id/unflag", itemHandler.UnflagItem)
+		v1.POST("/items/:id/clone", itemHandler.CloneItem)
+		v1.POST("/items/:id/touch", itemHandler.TouchItem)
+		v1.POST("/items", itemHandler.CreateItem)
+		v1.POST("/items/validate", itemHandler.ValidateItem)
+		v1.PUT("/items/:id", itemHandler.UpsertItem)
+		v1.PATCH("/items/batch", itemHandler.PatchItems)
+		v1.PATCH("/items/:id", itemHandler.PatchItem)
+		v1.DELETE("/items/:id", itemHandler.DeleteItem)
+	}
+
+	// Operational endpoints, off by default
+	if cfg.AdminEnabled {
+		admin := v1.Group("/admin")
+		admin.GET("/storage", adminHandler.StorageStats)
+		admin.POST("/flush-traces", adminHandler.FlushTraces)
+		admin.POST("/snapshot", adminHandler.SaveSnapshot)
+		admin.POST("/restore", adminHandler.RestoreSnapshot)
+		admin.POST("/gc", adminHandler.ForceGC)
+		base.GET("/debug/config", handlers.DebugConfig(cfg.Effective()))
+		logger.Info("Admin endpoints enabled")
+	}
+
+	return router
+}
@@ -0,0 +1,161 @@
+package server_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/misua/eks-with-otel/demo-app/internal/config"
+	"github.com/misua/eks-with-otel/demo-app/internal/server"
+	"github.com/misua/eks-with-otel/demo-app/internal/storage"
+	"github.com/sirupsen/logrus"
+)
+
+func TestNewRouterItemLifecycle(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	cfg := config.Defaults()
+	cfg.APIBasePath = "/demo"
+	srv := httptest.NewServer(server.NewRouter(storage.NewMemoryStorage(), logger, server.RouterOptions{Config: cfg}))
+	defer srv.Close()
+
+	call := func(method, path string, body any) (int, map[string]any) {
+		t.Helper()
+		var reader io.Reader
+		if body != nil {
+			data, _ := json.Marshal(body)
+			reader = bytes.NewReader(data)
+		}
+		req, _ := http.NewRequest(method, srv.URL+path, reader)
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := srv.Client().Do(req)
+		if err != nil {
+			t.Fatalf("%s %s: %v", method, path, err)
+		}
+		defer resp.Body.Close()
+		var decoded map[string]any
+		json.NewDecoder(resp.Body).Decode(&decoded)
+		return resp.StatusCode, decoded
+	}
+
+	status, created := call(http.MethodPost, "/demo/api/v1/items", map[string]string{"name": "Round trip", "description": "through NewRouter"})
+	if status != http.StatusCreated {
+		t.Fatalf("create: status %d, body %v", status, created)
+	}
+	id, _ := created["id"].(string)
+	item := "/demo/api/v1/items/" + id
+
+	steps := []struct {
+		name       string
+		method     string
+		path       string
+		body       any
+		wantStatus int
+		check      func(body map[string]any) bool
+	}{
+		{"health without base path", http.MethodGet, "/health", nil, http.StatusOK, nil},
+		{"readiness", http.MethodGet, "/readyz", nil, http.StatusOK, nil},
+		{"get", http.MethodGet, item, nil, http.StatusOK, func(b map[string]any) bool { return b["name"] == "Round trip" }},
+		{"list", http.MethodGet, "/demo/api/v1/items", nil, http.StatusOK, func(b map[string]any) bool { return b["count"] == 1.0 }},
+		{"update", http.MethodPut, item, map[string]string{"name": "Renamed"}, http.StatusOK, func(b map[string]any) bool {
+			return b["name"] == "Renamed" && b["version"] == 2.0
+		}},
+		{"delete", http.MethodDelete, item, nil, http.StatusOK, nil},
+		{"get deleted", http.MethodGet, item, nil, http.StatusNotFound, nil},
+		{"route outside base path", http.MethodGet, "/api/v1/items", nil, http.StatusNotFound, nil},
+	}
+	for _, step := range steps {
+		t.Run(step.name, func(t *testing.T) {
+			status, body := call(step.method, step.path, step.body)
+			if status != step.wantStatus {
+				t.Fatalf("status %d, want %d (body %v)", status, step.wantStatus, body)
+			}
+			if step.check != nil && !step.check(body) {
+				t.Errorf("unexpected body %v", body)
+			}
+		})
+	}
+}
+
+func TestEventStreamsDoNotTakeRequestSlots(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	tests := []struct {
+		name  string
+		limit func(cfg *config.Config)
+	}{
+		{"concurrency limit", func(cfg *config.Config) {
+			cfg.MaxConcurrent = 1
+			cfg.QueueTimeout = 200 * time.Millisecond
+		}},
+		{"load shedding", func(cfg *config.Config) { cfg.MaxInFlight = 1 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Defaults()
+			tt.limit(cfg)
+			srv := httptest.NewServer(server.NewRouter(storage.NewMemoryStorage(), logger, server.RouterOptions{Config: cfg}))
+			defer srv.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/api/v1/items/events", nil)
+			events := make(chan struct{}, 64)
+			go func() {
+				resp, err := srv.Client().Do(req)
+				if err != nil {
+					return
+				}
+				defer resp.Body.Close()
+				scanner := bufio.NewScanner(resp.Body)
+				for scanner.Scan() {
+					if strings.HasPrefix(scanner.Text(), "data:") {
+						events <- struct{}{}
+					}
+				}
+			}()
+
+			create := func() {
+				t.Helper()
+				body := strings.NewReader(`{"name":"item","description":"while streaming"}`)
+				resp, err := srv.Client().Post(srv.URL+"/api/v1/items", "application/json", body)
+				if err != nil {
+					t.Fatalf("create: %v", err)
+				}
+				resp.Body.Close()
+				if resp.StatusCode != http.StatusCreated {
+					t.Fatalf("create while a stream is open: status %d, want 201", resp.StatusCode)
+				}
+			}
+
+			// The stream sends nothing before the first event; once it has
+			// delivered one it is holding its request open
+			for streaming := false; !streaming; {
+				if ctx.Err() != nil {
+					t.Fatal("stream never delivered an event")
+				}
+				create()
+				select {
+				case <-events:
+					streaming = true
+				case <-time.After(50 * time.Millisecond):
+				}
+			}
+			for range 3 {
+				create()
+			}
+		})
+	}
+}
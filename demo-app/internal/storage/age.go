@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// ageBuckets are the upper bounds items are counted under by age; older
+// items fall into the last, unbounded bucket
+var ageBuckets = []time.Duration{
+	time.Minute,
+	5 * time.Minute,
+	15 * time.Minute,
+	time.Hour,
+	6 * time.Hour,
+	24 * time.Hour,
+}
+
+// ageSampleLimit bounds how many items one collection looks at. Larger stores
+// are sampled evenly across shards and the counts scaled up to the shard
+// sizes.
+const ageSampleLimit = 10000
+
+var ageGauge, _ = meter.Int64ObservableGauge(
+	"storage.items.age",
+	metric.WithDescription("Stored items by time since creation, one series per age bucket"),
+)
+
+// registerAgeGauge exports how many items currently fall into each age
+// bucket. The metrics API has no asynchronous histogram, so the distribution
+// is a gauge per bucket labelled with its age range in seconds as age.bucket,
+// e.g. "60-300" or "86400+". Each series counts the items of its own bucket
+// only, unlike a histogram's cumulative le buckets, so the series add up to
+// the item count.
+func (s *MemoryStorage) registerAgeGauge() {
+	labels := make([]attribute.Set, len(ageBuckets)+1)
+	for i, name := range ageBucketNames() {
+		labels[i] = attribute.NewSet(attribute.String("age.bucket", name))
+	}
+
+	s.registerCallback(func(_ context.Context, o metric.Observer) error {
+		for i, count := range s.ageDistribution(time.Now()) {
+			o.ObserveInt64(ageGauge, count, metric.WithAttributeSet(labels[i]))
+		}
+		return nil
+	}, ageGauge)
+}
+
+// ageDistribution counts items per age bucket, looking at no more than
+// ageSampleLimit of them. Each shard is read-locked on its own only for as
+// long as its share of the sample takes; map order makes the share random.
+func (s *MemoryStorage) ageDistribution(now time.Time) []int64 {
+	perShard := max(ageSampleLimit/len(s.shards), 1)
+	estimates := make([]float64, len(ageBuckets)+1)
+	for _, sh := range s.shards {
+		sh.mutex.RLock()
+		total := len(sh.items)
+		sampled := make([]int, len(estimates))
+		seen := 0
+		for _, item := range sh.items {
+			if seen == perShard {
+				break
+			}
+			sampled[ageBucket(now.Sub(item.CreatedAt))]++
+			seen++
+		}
+		sh.mutex.RUnlock()
+
+		if seen == 0 {
+			continue
+		}
+		scale := float64(total) / float64(seen)
+		for i, n := range sampled {
+			estimates[i] += float64(n) * scale
+		}
+	}
+
+	counts := make([]int64, len(estimates))
+	for i, estimate := range estimates {
+		counts[i] = int64(math.Round(estimate))
+	}
+	return counts
+}
+
+// ageBucketNames returns the age range of every bucket in seconds, lower
+// bound exclusive except for the first
+func ageBucketNames() []string {
+	names := make([]string, 0, len(ageBuckets)+1)
+	lower := "0"
+	for _, bound := range ageBuckets {
+		upper := strconv.FormatFloat(bound.Seconds(), 'f', -1, 64)
+		names = append(names, lower+"-"+upper)
+		lower = upper
+	}
+	return append(names, lower+"+")
+}
+
+// ageBucket returns the index of the bucket an age falls into
+func ageBucket(age time.Duration) int {
+	for i, bound := range ageBuckets {
+		if age <= bound {
+			return i
+		}
+	}
+	return len(ageBuckets)
+}
@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/misua/eks-with-otel/demo-app/internal/models"
+)
+
+func TestAgeBucketNames(t *testing.T) {
+	want := []string{"0-60", "60-300", "300-900", "900-3600", "3600-21600", "21600-86400", "86400+"}
+	if got := ageBucketNames(); !slices.Equal(got, want) {
+		t.Fatalf("ageBucketNames() = %v, want %v", got, want)
+	}
+}
+
+func TestAgeDistributionCountsEachBucketOnce(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStorage()
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		age    time.Duration
+		bucket int
+	}{
+		{30 * time.Second, 0},
+		{time.Minute, 0},
+		{2 * time.Minute, 1},
+		{10 * time.Minute, 2},
+		{30 * time.Minute, 3},
+		{2 * time.Hour, 4},
+		{12 * time.Hour, 5},
+		{48 * time.Hour, 6},
+	}
+
+	want := make([]int64, len(ageBuckets)+1)
+	for i, tt := range tests {
+		if got := ageBucket(tt.age); got != tt.bucket {
+			t.Errorf("ageBucket(%v) = %d, want %d", tt.age, got, tt.bucket)
+		}
+		item := models.NewItemWithID(fmt.Sprintf("item-%d", i), "item", "")
+		item.CreatedAt = now.Add(-tt.age)
+		item.UpdatedAt = item.CreatedAt
+		if _, err := s.Create(ctx, item); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+		want[tt.bucket]++
+	}
+
+	// Buckets are not cumulative: every item is counted in its own bucket only
+	got := s.ageDistribution(now)
+	if !slices.Equal(got, want) {
+		t.Errorf("ageDistribution() = %v, want %v", got, want)
+	}
+	var total int64
+	for _, n := range got {
+		total += n
+	}
+	if total != int64(len(tests)) {
+		t.Errorf("buckets add up to %d, want %d items", total, len(tests))
+	}
+}
@@ -0,0 +1,103 @@
+package storage
+
+import (
+	"context"
+	"sort"
+
+	"github.com/misua/eks-with-otel/demo-app/internal/models"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	// BatchUpdated marks an ID the patch was applied to
+	BatchUpdated = "updated"
+	// BatchNotFound marks an ID that does not exist
+	BatchNotFound = "not_found"
+)
+
+// BatchResult is the outcome of a batch operation for a single ID
+type BatchResult struct {
+	ID     string       `json:"id"`
+	Status string       `json:"status"`
+	Item   *models.Item `json:"item,omitempty"`
+}
+
+// PatchBatch applies the same patch to every listed item. All shards owning
+// the IDs are write-locked together, in shard order to rule out deadlocks, so
+// readers see either none or all of the batch. Results follow the order of
+// ids; duplicate IDs are patched once.
+func (s *MemoryStorage) PatchBatch(ctx context.Context, ids []string, patch models.ItemPatch) ([]BatchResult, error) {
+	ctx, span := startSpan(ctx, "storage.patch_batch")
+	defer span.End()
+
+	if err := checkContext(ctx, span); err != nil {
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("batch.size", len(ids)))
+
+	// Collect the distinct shards involved, in a fixed order
+	index := make(map[*shard]int, len(s.shards))
+	for i, sh := range s.shards {
+		index[sh] = i
+	}
+	var locked []*shard
+	seen := make(map[*shard]bool)
+	for _, id := range ids {
+		if sh := s.shardFor(id); !seen[sh] {
+			seen[sh] = true
+			locked = append(locked, sh)
+		}
+	}
+	sort.Slice(locked, func(a, b int) bool { return index[locked[a]] < index[locked[b]] })
+
+	var events []StorageEvent
+	defer func() {
+		for _, event := range events {
+			s.notify(event)
+		}
+	}()
+
+	for _, sh := range locked {
+		sh.lock(ctx, span, "patch_batch")
+		defer sh.mutex.Unlock()
+	}
+
+	if err := checkContext(ctx, span); err != nil {
+		return nil, err
+	}
+
+	results := make([]BatchResult, 0, len(ids))
+	patched := make(map[string]bool, len(ids))
+	updated, notFound := 0, 0
+	for _, id := range ids {
+		sh := s.shardFor(id)
+		item, exists := sh.items[id]
+		if !exists {
+			notFound++
+			results = append(results, BatchResult{ID: id, Status: BatchNotFound})
+			continue
+		}
+		if !patched[id] {
+			patched[id] = true
+			// Readers may hold the stored pointer, so the new version is a copy
+			s.recordHistory(sh, item)
+			applied := *item
+			applied.Apply(patch)
+			s.bytes.Add(estimateItemBytes(&applied) - estimateItemBytes(item))
+			item = &applied
+			sh.items[id] = item
+			events = append(events, newEvent(OperationUpdate, item))
+			updated++
+		}
+		snapshot := *item
+		results = append(results, BatchResult{ID: id, Status: BatchUpdated, Item: &snapshot})
+	}
+
+	span.SetAttributes(
+		attribute.Int("batch.updated", updated),
+		attribute.Int("batch.not_found", notFound),
+		attribute.Int("batch.shards_locked", len(locked)),
+	)
+	return results, nil
+}
@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync/atomic"
+	"testing"
+
+	"github.com/misua/eks-with-otel/demo-app/internal/models"
+)
+
+// loadgenOperations mirrors the load generator's mixed traffic weights:
+// health checks count the items, listings read them all
+var loadgenOperations = []string{
+	"count", "count", "count",
+	"create", "create",
+	"list", "list", "list",
+	"get", "get",
+	"update",
+	"delete",
+}
+
+// BenchmarkMemoryStorageLoadgenMix compares a single lock with the default
+// shard count under the load generator's operation mix
+func BenchmarkMemoryStorageLoadgenMix(b *testing.B) {
+	for _, shards := range []int{1, defaultShardCount} {
+		b.Run(fmt.Sprintf("shards=%d", shards), func(b *testing.B) {
+			benchmarkLoadgenMix(b, NewShardedMemoryStorage(shards))
+		})
+	}
+}
+
+func benchmarkLoadgenMix(b *testing.B, s *MemoryStorage) {
+	ctx := context.Background()
+	seeded := make([]string, 500)
+	for i := range seeded {
+		item, _ := s.Create(ctx, models.NewItem(fmt.Sprintf("seed %d", i), "benchmark item"))
+		seeded[i] = item.ID
+	}
+
+	var seed atomic.Int64
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		rng := rand.New(rand.NewSource(seed.Add(1)))
+		// Each goroutine deletes only what it created, so the seeded items
+		// stay available for reads and updates
+		var created []string
+		for pb.Next() {
+			switch loadgenOperations[rng.Intn(len(loadgenOperations))] {
+			case "count":
+				s.Count(ctx)
+			case "create":
+				item, _ := s.Create(ctx, models.NewItem("created", "benchmark item"))
+				created = append(created, item.ID)
+			case "list":
+				s.GetAll(ctx)
+			case "get":
+				s.GetByID(ctx, seeded[rng.Intn(len(seeded))])
+			case "update":
+				s.Update(ctx, seeded[rng.Intn(len(seeded))], "updated", "")
+			case "delete":
+				if len(created) > 0 {
+					s.Delete(ctx, created[len(created)-1])
+					created = created[:len(created)-1]
+				}
+			}
+		}
+	})
+}
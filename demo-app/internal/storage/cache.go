@@ -0,0 +1,158 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/misua/eks-with-otel/demo-app/internal/models"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Read cache lookup results recorded on storage.cache.lookups and as cache.result
+const (
+	cacheHit       = "hit"
+	cacheMiss      = "miss"
+	cacheCoalesced = "coalesced"
+)
+
+// cacheStripes is the number of generation counters IDs are spread over
+const cacheStripes = 64
+
+var cacheLookups, _ = meter.Int64Counter(
+	"storage.cache.lookups",
+	metric.WithDescription("GetByID lookups answered by the read cache, by result"),
+)
+
+// readCache keeps recently read items for a short TTL so repeated GetByID
+// calls skip the shard locks, and lets concurrent misses for the same ID
+// share a single storage read. Entries are dropped on every mutation through
+// the observer list.
+//
+// A read that started before a mutation must not put the old item back after
+// the mutation invalidated it, so every fill checks that the generation of the
+// ID's stripe did not move while it was reading.
+type readCache struct {
+	ttl time.Duration
+
+	mu        sync.Mutex
+	entries   map[string]cacheEntry
+	inflight  map[string]*cacheCall
+	lastSweep time.Time
+
+	generations [cacheStripes]atomic.Uint64
+}
+
+type cacheEntry struct {
+	item    models.Item
+	expires time.Time
+}
+
+// cacheCall is a storage read shared by concurrent misses for one ID
+type cacheCall struct {
+	done chan struct{}
+	item *models.Item
+	err  error
+}
+
+func newReadCache(ttl time.Duration) *readCache {
+	return &readCache{
+		ttl:      ttl,
+		entries:  make(map[string]cacheEntry),
+		inflight: make(map[string]*cacheCall),
+	}
+}
+
+// get returns the item for id from the cache or, on a miss, from load,
+// joining a read already in flight for the same ID. It reports how the
+// lookup was answered.
+func (c *readCache) get(ctx context.Context, id string, load func() (*models.Item, error)) (*models.Item, string, error) {
+	now := time.Now()
+
+	c.mu.Lock()
+	if entry, ok := c.entries[id]; ok && now.Before(entry.expires) {
+		c.mu.Unlock()
+		item := entry.item
+		return &item, cacheHit, nil
+	}
+	if call, ok := c.inflight[id]; ok {
+		c.mu.Unlock()
+		select {
+		case <-call.done:
+			// The shared read failing on its caller's context says nothing
+			// about this one, read again under our own
+			if ctx.Err() == nil && (errors.Is(call.err, context.Canceled) || errors.Is(call.err, context.DeadlineExceeded)) {
+				item, err := load()
+				return item, cacheMiss, err
+			}
+			return call.item, cacheCoalesced, call.err
+		case <-ctx.Done():
+			return nil, cacheCoalesced, ctx.Err()
+		}
+	}
+	call := &cacheCall{done: make(chan struct{})}
+	c.inflight[id] = call
+	c.mu.Unlock()
+
+	generation := c.stripe(id).Load()
+	call.item, call.err = load()
+
+	c.mu.Lock()
+	delete(c.inflight, id)
+	if call.err == nil && c.stripe(id).Load() == generation {
+		c.sweep(now)
+		c.entries[id] = cacheEntry{item: *call.item, expires: now.Add(c.ttl)}
+	}
+	c.mu.Unlock()
+	close(call.done)
+
+	return call.item, cacheMiss, call.err
+}
+
+// invalidate drops the cached item, if any, and fails fills still in flight
+// for it
+func (c *readCache) invalidate(id string) {
+	c.stripe(id).Add(1)
+	c.mu.Lock()
+	delete(c.entries, id)
+	c.mu.Unlock()
+}
+
+// clear drops every cached item, e.g. after the store was replaced
+func (c *readCache) clear() {
+	for i := range c.generations {
+		c.generations[i].Add(1)
+	}
+	c.mu.Lock()
+	clear(c.entries)
+	c.mu.Unlock()
+}
+
+// sweep drops expired entries at most once per TTL so items that are never
+// read again do not pile up. c.mu must be held.
+func (c *readCache) sweep(now time.Time) {
+	if now.Sub(c.lastSweep) < c.ttl {
+		return
+	}
+	c.lastSweep = now
+	for id, entry := range c.entries {
+		if !now.Before(entry.expires) {
+			delete(c.entries, id)
+		}
+	}
+}
+
+func (c *readCache) stripe(id string) *atomic.Uint64 {
+	h := fnv.New32a()
+	h.Write([]byte(id))
+	return &c.generations[h.Sum32()%cacheStripes]
+}
+
+// recordCacheLookup counts a lookup by how the cache answered it
+func recordCacheLookup(ctx context.Context, result string) {
+	cacheLookups.Add(ctx, 1, metric.WithAttributes(attribute.String("result", result)))
+}
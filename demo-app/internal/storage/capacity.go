@@ -0,0 +1,159 @@
+package storage
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/misua/eks-with-otel/demo-app/internal/models"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// FullPolicyReject fails creates with ErrStorageFull once MaxItems is reached
+	FullPolicyReject = "reject"
+	// FullPolicyEvict deletes the oldest items to make room for new ones
+	FullPolicyEvict = "evict"
+)
+
+// maxEvictAttempts bounds how often a create retries eviction when concurrent
+// deletes keep removing the chosen victim first
+const maxEvictAttempts = 8
+
+// reserve claims room for one more item. Under the reject policy it fails once
+// maxItems is reached; under the evict policy room was made before locking, so
+// it always succeeds and concurrent creates may overshoot the limit briefly.
+func (s *MemoryStorage) reserve() bool {
+	if s.maxItems <= 0 || s.fullPolicy != FullPolicyReject {
+		s.size.Add(1)
+		return true
+	}
+	for {
+		n := s.size.Load()
+		if n >= int64(s.maxItems) {
+			return false
+		}
+		if s.size.CompareAndSwap(n, n+1) {
+			return true
+		}
+	}
+}
+
+// makeRoom evicts the oldest items until there is room for one more under the
+// evict policy. It must be called without holding any shard lock, as deleting
+// a victim locks its shard, and returns the number of items evicted.
+func (s *MemoryStorage) makeRoom(ctx context.Context, span trace.Span) int {
+	if s.maxItems <= 0 || s.fullPolicy != FullPolicyEvict {
+		return 0
+	}
+
+	evicted := 0
+	for attempts := 0; s.size.Load() >= int64(s.maxItems) && attempts < maxEvictAttempts; attempts++ {
+		id := s.creations.oldest()
+		if id == "" {
+			break
+		}
+		// Another request may have deleted the victim meanwhile, retry with the next oldest
+		if err := s.Delete(ctx, id); err != nil {
+			continue
+		}
+		evicted++
+		s.evictions.Add(1)
+		span.AddEvent("storage.evicted", trace.WithAttributes(attribute.String("item.id", id)))
+	}
+	return evicted
+}
+
+// creationIndex orders the stored items by creation time, oldest first with
+// ties broken by ID, so eviction finds its victim without scanning every
+// shard. It is a min-heap with the position of each ID, kept only under the
+// evict policy. Callers update it while holding the item's shard lock; its
+// own mutex is taken last and never held while locking a shard.
+type creationIndex struct {
+	mu      sync.Mutex
+	entries []creationEntry
+	// positions maps each indexed ID to its slot in entries
+	positions map[string]int
+}
+
+type creationEntry struct {
+	id      string
+	created time.Time
+}
+
+func newCreationIndex() *creationIndex {
+	return &creationIndex{positions: make(map[string]int)}
+}
+
+// add indexes an item that was just stored
+func (x *creationIndex) add(item *models.Item) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	heap.Push(x, creationEntry{id: item.ID, created: item.CreatedAt})
+}
+
+// remove drops an ID that was just deleted, if it is indexed
+func (x *creationIndex) remove(id string) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if i, ok := x.positions[id]; ok {
+		heap.Remove(x, i)
+	}
+}
+
+// reset replaces the index with the given items, after a restore
+func (x *creationIndex) reset(items []*models.Item) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.entries = make([]creationEntry, 0, len(items))
+	x.positions = make(map[string]int, len(items))
+	for _, item := range items {
+		x.positions[item.ID] = len(x.entries)
+		x.entries = append(x.entries, creationEntry{id: item.ID, created: item.CreatedAt})
+	}
+	heap.Init(x)
+}
+
+// oldest returns the ID of the item created first, or "" when empty
+func (x *creationIndex) oldest() string {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if len(x.entries) == 0 {
+		return ""
+	}
+	return x.entries[0].id
+}
+
+// Len, Less, Swap, Push and Pop implement heap.Interface; use the methods above
+
+func (x *creationIndex) Len() int { return len(x.entries) }
+
+func (x *creationIndex) Less(i, j int) bool {
+	a, b := x.entries[i], x.entries[j]
+	if c := a.created.Compare(b.created); c != 0 {
+		return c < 0
+	}
+	return a.id < b.id
+}
+
+func (x *creationIndex) Swap(i, j int) {
+	x.entries[i], x.entries[j] = x.entries[j], x.entries[i]
+	x.positions[x.entries[i].id] = i
+	x.positions[x.entries[j].id] = j
+}
+
+func (x *creationIndex) Push(v any) {
+	entry := v.(creationEntry)
+	x.positions[entry.id] = len(x.entries)
+	x.entries = append(x.entries, entry)
+}
+
+func (x *creationIndex) Pop() any {
+	last := x.entries[len(x.entries)-1]
+	x.entries = x.entries[:len(x.entries)-1]
+	delete(x.positions, last.id)
+	return last
+}
@@ -0,0 +1,29 @@
+package storage
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestCloseReleasesTheStore(t *testing.T) {
+	collected := make(chan struct{})
+	func() {
+		s := NewMemoryStorage()
+		runtime.SetFinalizer(s, func(*MemoryStorage) { close(collected) })
+		if err := s.Close(); err != nil {
+			t.Fatalf("Close: %v", err)
+		}
+	}()
+
+	// Gauge callbacks left registered would keep the closed store reachable
+	for range 20 {
+		runtime.GC()
+		select {
+		case <-collected:
+			return
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+	t.Fatal("closed store is still reachable")
+}
@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/misua/eks-with-otel/demo-app/internal/models"
+)
+
+// TestWritesDoNotMutateListedItems runs each write alongside listings that
+// marshal the returned items after the shard locks are released, as the
+// handlers do. Run with -race: writes must store a new copy of the item
+// instead of changing the one readers may hold.
+func TestWritesDoNotMutateListedItems(t *testing.T) {
+	tests := []struct {
+		name  string
+		write func(ctx context.Context, s *MemoryStorage, id string) error
+	}{
+		{"Touch", func(ctx context.Context, s *MemoryStorage, id string) error {
+			_, err := s.Touch(ctx, id)
+			return err
+		}},
+		{"Update", func(ctx context.Context, s *MemoryStorage, id string) error {
+			_, err := s.Update(ctx, id, "updated", "")
+			return err
+		}},
+		{"Upsert", func(ctx context.Context, s *MemoryStorage, id string) error {
+			_, _, err := s.Upsert(ctx, id, models.NewItem("upserted", "replaced"))
+			return err
+		}},
+		{"PatchItem", func(ctx context.Context, s *MemoryStorage, id string) error {
+			_, err := s.PatchItem(ctx, id, func(item *models.Item) error {
+				item.Apply(models.ItemPatch{Description: &id})
+				return nil
+			})
+			return err
+		}},
+		{"PatchBatch", func(ctx context.Context, s *MemoryStorage, id string) error {
+			name := "batch patched"
+			_, err := s.PatchBatch(ctx, []string{id}, models.ItemPatch{Name: &name})
+			return err
+		}},
+		{"SetFlag", func(ctx context.Context, s *MemoryStorage, id string) error {
+			item, err := s.GetByID(ctx, id)
+			if err != nil {
+				return err
+			}
+			_, err = s.SetFlag(ctx, id, !item.Flagged)
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s := NewMemoryStorage()
+			item, err := s.Create(ctx, models.NewItem("item", "listed while written"))
+			if err != nil {
+				t.Fatalf("create: %v", err)
+			}
+			listed, err := s.GetAll(ctx)
+			if err != nil {
+				t.Fatalf("get all: %v", err)
+			}
+			before, _ := json.Marshal(listed)
+
+			var wg sync.WaitGroup
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				for range 100 {
+					if err := tt.write(ctx, s, item.ID); err != nil {
+						t.Errorf("write: %v", err)
+						return
+					}
+				}
+			}()
+			go func() {
+				defer wg.Done()
+				for range 100 {
+					items, _ := s.GetAll(ctx)
+					_, _ = json.Marshal(items)
+				}
+			}()
+			wg.Wait()
+
+			// A listing taken before the writes still shows the old version
+			if after, _ := json.Marshal(listed); string(after) != string(before) {
+				t.Errorf("listed item changed by %s:\n%s\nwant\n%s", tt.name, after, before)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package storage
+
+import "errors"
+
+// Error classes. Every storage error wraps one of them, and errors returned
+// by backends add context with fmt.Errorf and %w, so callers branch with
+// errors.Is or the Is* helpers below rather than comparing with ==.
+var (
+	ErrNotFound = errors.New("not found")
+	ErrConflict = errors.New("conflict")
+	ErrFull     = errors.New("capacity exceeded")
+	ErrInvalid  = errors.New("invalid argument")
+)
+
+// Specific errors, each belonging to one class
+var (
+	ErrItemNotFound = classError("item not found", ErrNotFound)
+	ErrItemExists   = classError("item already exists", ErrConflict)
+	// ErrVersionMismatch is returned by conditional writes whose expected
+	// version no longer matches the stored item
+	ErrVersionMismatch = classError("item version mismatch", ErrConflict)
+	// ErrStorageFull is returned by Create when MaxItems is reached under the reject policy
+	ErrStorageFull = classError("storage full", ErrFull)
+	// ErrUnsupportedField is returned by GroupBy for fields it cannot group on
+	ErrUnsupportedField = classError("unsupported group-by field", ErrInvalid)
+	// ErrInvalidSnapshot is returned by LoadSnapshot for files it cannot restore
+	ErrInvalidSnapshot = classError("invalid snapshot", ErrInvalid)
+)
+
+// IsNotFound reports whether err means the addressed item does not exist
+func IsNotFound(err error) bool { return errors.Is(err, ErrNotFound) }
+
+// IsConflict reports whether err means the write clashes with stored state
+func IsConflict(err error) bool { return errors.Is(err, ErrConflict) }
+
+// IsFull reports whether err means the backend has no room left
+func IsFull(err error) bool { return errors.Is(err, ErrFull) }
+
+// IsInvalid reports whether err means the request itself was unacceptable
+func IsInvalid(err error) bool { return errors.Is(err, ErrInvalid) }
+
+// storageError is a sentinel with its own message that unwraps to its class
+type storageError struct {
+	msg   string
+	class error
+}
+
+func classError(msg string, class error) error {
+	return &storageError{msg: msg, class: class}
+}
+
+func (e *storageError) Error() string { return e.msg }
+func (e *storageError) Unwrap() error { return e.class }
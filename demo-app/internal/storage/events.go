@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/misua/eks-with-otel/demo-app/internal/models"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Operation identifies the kind of mutation carried by a StorageEvent
+type Operation string
+
+const (
+	OperationCreate Operation = "create"
+	OperationUpdate Operation = "update"
+	OperationDelete Operation = "delete"
+)
+
+// StorageEvent describes a successful mutation. Item is a copy taken at the
+// time of the mutation, so observers may keep it without further locking.
+type StorageEvent struct {
+	Operation Operation
+	Item      models.Item
+	Timestamp time.Time
+}
+
+// Observer is notified after each successful mutation
+type Observer func(event StorageEvent)
+
+// mutationCounter is a plain monotonic counter: creates and deletes per second
+// are derived by the metrics backend, e.g. rate(storage_mutations_total[1m]),
+// so the storage needs no window bookkeeping or timers of its own
+var mutationCounter, _ = meter.Int64Counter(
+	"storage.mutations",
+	metric.WithDescription("Number of successful storage mutations by operation"),
+	metric.WithUnit("{mutation}"),
+)
+
+// AddObserver registers an observer for create/update/delete events.
+// Observers are called synchronously outside the storage locks, so they may
+// call back into the storage but should return quickly.
+func (s *MemoryStorage) AddObserver(observer Observer) {
+	s.observersMu.Lock()
+	defer s.observersMu.Unlock()
+
+	s.observers = append(s.observers, observer)
+}
+
+// notify delivers an event to all observers, ignoring the zero event left
+// behind by failed operations
+func (s *MemoryStorage) notify(event StorageEvent) {
+	if event.Operation == "" {
+		return
+	}
+
+	s.observersMu.RLock()
+	observers := s.observers
+	s.observersMu.RUnlock()
+
+	for _, observer := range observers {
+		observer(event)
+	}
+}
+
+// newEvent snapshots the item for an event
+func newEvent(op Operation, item *models.Item) StorageEvent {
+	return StorageEvent{
+		Operation: op,
+		Item:      *item,
+		Timestamp: time.Now(),
+	}
+}
+
+// countMutation is the built-in observer driving the mutation counter
+func countMutation(event StorageEvent) {
+	mutationCounter.Add(context.Background(), 1, metric.WithAttributes(
+		attribute.String("operation", string(event.Operation)),
+	))
+}
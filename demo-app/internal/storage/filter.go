@@ -0,0 +1,39 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/misua/eks-with-otel/demo-app/internal/models"
+)
+
+// TimeRange bounds item timestamps. Zero bounds are open, and all set bounds
+// must hold for an item to match.
+type TimeRange struct {
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	UpdatedAfter  time.Time
+	UpdatedBefore time.Time
+}
+
+// IsZero reports whether no bound is set
+func (r TimeRange) IsZero() bool {
+	return r.CreatedAfter.IsZero() && r.CreatedBefore.IsZero() &&
+		r.UpdatedAfter.IsZero() && r.UpdatedBefore.IsZero()
+}
+
+// Matches reports whether the item falls within every set bound
+func (r TimeRange) Matches(item *models.Item) bool {
+	if !r.CreatedAfter.IsZero() && !item.CreatedAt.After(r.CreatedAfter) {
+		return false
+	}
+	if !r.CreatedBefore.IsZero() && !item.CreatedAt.Before(r.CreatedBefore) {
+		return false
+	}
+	if !r.UpdatedAfter.IsZero() && !item.UpdatedAt.After(r.UpdatedAfter) {
+		return false
+	}
+	if !r.UpdatedBefore.IsZero() && !item.UpdatedAt.Before(r.UpdatedBefore) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/misua/eks-with-otel/demo-app/internal/models"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// SetFlag marks or unmarks an item. Changing the flag is an update: the
+// previous version goes to history and observers are notified. Setting the
+// flag the item already has changes nothing.
+func (s *MemoryStorage) SetFlag(ctx context.Context, id string, flagged bool) (*models.Item, error) {
+	ctx, span := startSpan(ctx, "storage.set_flag")
+	defer span.End()
+
+	if err := checkContext(ctx, span); err != nil {
+		return nil, err
+	}
+
+	span.SetAttributes(
+		attribute.String("item.id", id),
+		attribute.Bool("item.flagged", flagged),
+	)
+
+	var event StorageEvent
+	defer func() { s.notify(event) }()
+
+	sh := s.shardFor(id)
+	sh.lock(ctx, span, "set_flag")
+	defer sh.mutex.Unlock()
+
+	if err := checkContext(ctx, span); err != nil {
+		return nil, err
+	}
+
+	item, exists := sh.items[id]
+	if !exists {
+		span.SetAttributes(attribute.Bool("item.found", false))
+		err := fmt.Errorf("set flag %s: %w", id, ErrItemNotFound)
+		span.RecordError(err)
+		return nil, err
+	}
+
+	before := *item
+	if item.Flagged != flagged {
+		// Readers may hold the stored pointer, so the new version is a copy
+		s.recordHistory(sh, item)
+		flaggedItem := *item
+		flaggedItem.SetFlagged(flagged)
+		sh.items[id] = &flaggedItem
+		item = &flaggedItem
+		event = newEvent(OperationUpdate, item)
+	}
+
+	span.SetAttributes(
+		attribute.Bool("item.found", true),
+		attribute.Bool("flag.changed", before.Flagged != flagged),
+	)
+	snapshot := *item
+	return &snapshot, nil
+}
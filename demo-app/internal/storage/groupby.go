@@ -0,0 +1,76 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/misua/eks-with-otel/demo-app/internal/models"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// groupKey returns the function extracting the grouped value from an item,
+// or nil when the field is not supported. Items without the field are skipped.
+func groupKey(field string) func(item *models.Item) (string, bool) {
+	switch field {
+	case "owner":
+		return func(item *models.Item) (string, bool) { return item.Owner, true }
+	case "name":
+		return func(item *models.Item) (string, bool) { return item.Name, true }
+	}
+	if key, ok := strings.CutPrefix(field, "metadata."); ok && key != "" {
+		return func(item *models.Item) (string, bool) {
+			value, exists := item.Metadata[key]
+			if !exists {
+				return "", false
+			}
+			return fmt.Sprint(value), true
+		}
+	}
+	return nil
+}
+
+// GroupBy counts items per distinct value of field: owner, name or
+// metadata.<key>. A non-empty owner restricts the count to that owner's items.
+// All shards are read-locked together, in shard order, so the counts are a
+// consistent snapshot.
+func (s *MemoryStorage) GroupBy(ctx context.Context, field, owner string) (map[string]int, error) {
+	ctx, span := startSpan(ctx, "storage.group_by")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("groupby.field", field))
+
+	key := groupKey(field)
+	if key == nil {
+		span.RecordError(ErrUnsupportedField)
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedField, field)
+	}
+
+	if err := checkContext(ctx, span); err != nil {
+		return nil, err
+	}
+
+	for _, sh := range s.shards {
+		sh.rlock(ctx, span, "group_by")
+		defer sh.mutex.RUnlock()
+	}
+
+	if err := checkContext(ctx, span); err != nil {
+		return nil, err
+	}
+
+	groups := make(map[string]int)
+	for _, sh := range s.shards {
+		for _, item := range sh.items {
+			if owner != "" && item.Owner != owner {
+				continue
+			}
+			if value, ok := key(item); ok {
+				groups[value]++
+			}
+		}
+	}
+
+	span.SetAttributes(attribute.Int("groupby.groups", len(groups)))
+	return groups, nil
+}
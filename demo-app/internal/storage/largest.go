@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"context"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/misua/eks-with-otel/demo-app/internal/models"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// GetLargest returns up to limit items with the longest descriptions, longest
+// first, counting characters as the description limit does. Ties are ordered
+// by ID. A non-empty owner restricts the result to that owner's items.
+func (s *MemoryStorage) GetLargest(ctx context.Context, limit int, owner string) ([]*models.Item, error) {
+	ctx, span := startSpan(ctx, "storage.get_largest_items")
+	defer span.End()
+
+	span.SetAttributes(attribute.Int("largest.limit", limit))
+
+	var items []*models.Item
+	var err error
+	if owner != "" {
+		items, err = s.GetAllForOwner(ctx, owner)
+	} else {
+		items, err = s.GetAll(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Lengths are computed once, counting runes is linear in the description
+	lengths := make(map[*models.Item]int, len(items))
+	for _, item := range items {
+		lengths[item] = utf8.RuneCountInString(item.Description)
+	}
+	sort.Slice(items, func(a, b int) bool {
+		if la, lb := lengths[items[a]], lengths[items[b]]; la != lb {
+			return la > lb
+		}
+		return items[a].ID < items[b].ID
+	})
+	if len(items) > limit {
+		items = items[:limit]
+	}
+
+	span.SetAttributes(attribute.Int("items.count", len(items)))
+	return items, nil
+}
@@ -2,162 +2,733 @@ package storage
 
 import (
 	"context"
-	"errors"
+	"fmt"
+	"hash/fnv"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/misua/eks-with-otel/demo-app/internal/models"
+	"github.com/misua/eks-with-otel/demo-app/internal/telemetry"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// lockWaitThreshold is the lock wait above which a contention event is added to the span
+const lockWaitThreshold = 5 * time.Millisecond
+
 var (
-	ErrItemNotFound = errors.New("item not found")
-	tracer          = otel.Tracer("storage")
+	tracer = otel.Tracer("storage")
+	meter  = otel.Meter("storage")
+
+	lockWaitHistogram, _ = meter.Float64Histogram(
+		"storage.lock_wait",
+		metric.WithDescription("Time spent waiting to acquire the storage lock"),
+		metric.WithUnit("ms"),
+	)
 )
 
-// MemoryStorage provides in-memory storage for items with OpenTelemetry tracing
+const (
+	// defaultShardCount is the number of shards used by NewMemoryStorage
+	defaultShardCount = 16
+	// defaultHistoryLimit is the number of past versions kept per item
+	defaultHistoryLimit = 10
+)
+
+// Options configures a MemoryStorage
+type Options struct {
+	// Shards is the number of independently locked partitions
+	Shards int
+	// HistoryLimit is the number of past versions kept per item; zero disables history
+	HistoryLimit int
+	// MaxItems caps the number of stored items; zero means unlimited
+	MaxItems int
+	// FullPolicy selects what Create does at MaxItems: FullPolicyReject or
+	// FullPolicyEvict. Anything else is treated as reject.
+	FullPolicy string
+	// ReadCacheTTL keeps GetByID results for this long; zero disables the cache
+	ReadCacheTTL time.Duration
+}
+
+// MemoryStorage provides in-memory storage for items with OpenTelemetry tracing.
+// Items are spread over shards keyed by a hash of the item ID, each with its own
+// map and lock, so operations on different items rarely contend.
 type MemoryStorage struct {
-	items map[string]*models.Item
-	mutex sync.RWMutex
+	shards       []*shard
+	size         atomic.Int64
+	historyLimit int
+	maxItems     int
+	fullPolicy   string
+	evictions    atomic.Int64
+	// creations orders items for eviction, nil unless FullPolicy is evict
+	creations *creationIndex
+	// bytes is the running footprint estimate behind the storage.memory gauge
+	bytes atomic.Int64
+	// cache serves repeated GetByID calls when ReadCacheTTL is set
+	cache *readCache
+
+	observersMu sync.RWMutex
+	observers   []Observer
+
+	// metricCallbacks are the gauge callbacks Close unregisters
+	metricCallbacks []metric.Registration
+
+	// snapshotStop and snapshotDone drive the StartSnapshots goroutine
+	snapshotStop chan struct{}
+	snapshotDone chan struct{}
+	closeOnce    sync.Once
+}
+
+// shard is a single partition of the store guarded by its own lock
+type shard struct {
+	items   map[string]*models.Item
+	history map[string][]models.Item
+	mutex   sync.RWMutex
+
+	// lockWaits and lockWaitNanos back the averages in DebugStats
+	lockWaits     atomic.Int64
+	lockWaitNanos atomic.Int64
 }
 
 // NewMemoryStorage creates a new in-memory storage instance
 func NewMemoryStorage() *MemoryStorage {
-	return &MemoryStorage{
-		items: make(map[string]*models.Item),
+	return NewShardedMemoryStorage(defaultShardCount)
+}
+
+// NewShardedMemoryStorage creates a new in-memory storage instance with the
+// given number of shards. A single shard behaves like one global lock.
+func NewShardedMemoryStorage(shardCount int) *MemoryStorage {
+	return NewMemoryStorageWithOptions(Options{
+		Shards:       shardCount,
+		HistoryLimit: defaultHistoryLimit,
+	})
+}
+
+// NewMemoryStorageWithOptions creates a new in-memory storage instance
+func NewMemoryStorageWithOptions(opts Options) *MemoryStorage {
+	if opts.Shards < 1 {
+		opts.Shards = 1
+	}
+	if opts.FullPolicy != FullPolicyEvict {
+		opts.FullPolicy = FullPolicyReject
 	}
+	s := &MemoryStorage{
+		shards:       make([]*shard, opts.Shards),
+		historyLimit: opts.HistoryLimit,
+		maxItems:     opts.MaxItems,
+		fullPolicy:   opts.FullPolicy,
+	}
+	if opts.MaxItems > 0 && opts.FullPolicy == FullPolicyEvict {
+		s.creations = newCreationIndex()
+	}
+	for i := range s.shards {
+		s.shards[i] = &shard{
+			items:   make(map[string]*models.Item),
+			history: make(map[string][]models.Item),
+		}
+	}
+	s.AddObserver(countMutation)
+	if opts.ReadCacheTTL > 0 {
+		s.cache = newReadCache(opts.ReadCacheTTL)
+		s.AddObserver(func(event StorageEvent) { s.cache.invalidate(event.Item.ID) })
+	}
+	s.registerMemoryGauge()
+	s.registerAgeGauge()
+	return s
 }
 
-// Create stores a new item and returns it
+// shardFor returns the shard owning the given item ID
+func (s *MemoryStorage) shardFor(id string) *shard {
+	h := fnv.New32a()
+	h.Write([]byte(id))
+	return s.shards[h.Sum32()%uint32(len(s.shards))]
+}
+
+// checkContext returns the context error, if any, and records it on the span
+// so cancelled or timed-out requests are visible in the storage layer
+func checkContext(ctx context.Context, span trace.Span) error {
+	if err := ctx.Err(); err != nil {
+		span.RecordError(err)
+		span.SetAttributes(attribute.Bool("context.cancelled", true))
+		return err
+	}
+	return nil
+}
+
+// lock acquires the shard's write lock and records how long it had to wait
+func (s *shard) lock(ctx context.Context, span trace.Span, operation string) {
+	start := time.Now()
+	s.mutex.Lock()
+	s.recordLockWait(ctx, span, operation, "write", time.Since(start))
+}
+
+// rlock acquires the shard's read lock and records how long it had to wait
+func (s *shard) rlock(ctx context.Context, span trace.Span, operation string) {
+	start := time.Now()
+	s.mutex.RLock()
+	s.recordLockWait(ctx, span, operation, "read", time.Since(start))
+}
+
+// recordLockWait reports the lock wait as a metric, and as a span event when
+// it exceeds lockWaitThreshold so uncontended calls stay cheap to trace
+func (s *shard) recordLockWait(ctx context.Context, span trace.Span, operation, mode string, wait time.Duration) {
+	s.lockWaits.Add(1)
+	s.lockWaitNanos.Add(int64(wait))
+	addLockWait(span, wait)
+
+	waitMs := float64(wait) / float64(time.Millisecond)
+	lockWaitHistogram.Record(ctx, waitMs, metric.WithAttributes(
+		attribute.String("operation", operation),
+		attribute.String("lock.mode", mode),
+	))
+
+	if wait > lockWaitThreshold {
+		span.AddEvent("storage.lock_contention", trace.WithAttributes(
+			attribute.String("lock.mode", mode),
+			attribute.Float64("lock.wait_ms", waitMs),
+		))
+	}
+}
+
+// Create stores a new item and returns it. It fails with ErrItemExists when
+// the ID is taken.
 func (s *MemoryStorage) Create(ctx context.Context, item *models.Item) (*models.Item, error) {
-	ctx, span := tracer.Start(ctx, "storage.create_item")
+	ctx, span := startSpan(ctx, "storage.create_item")
 	defer span.End()
 
+	if err := checkContext(ctx, span); err != nil {
+		return nil, err
+	}
+
 	span.SetAttributes(
 		attribute.String("item.id", item.ID),
-		attribute.String("item.name", item.Name),
+		attribute.String("tenant.id", item.Owner),
 	)
+	telemetry.SetDetail(span, attribute.String("item.name", item.Name))
 
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
+	sh := s.shardFor(item.ID)
+
+	if s.maxItems > 0 {
+		span.SetAttributes(
+			attribute.Int("storage.max_items", s.maxItems),
+			attribute.String("storage.full_policy", s.fullPolicy),
+		)
+		// Refuse a taken ID before evicting, a rejected create must not cost
+		// the oldest items their place
+		sh.rlock(ctx, span, "create")
+		_, exists := sh.items[item.ID]
+		sh.mutex.RUnlock()
+		if exists {
+			return nil, createConflict(span, item.ID)
+		}
+		if evicted := s.makeRoom(ctx, span); evicted > 0 {
+			span.SetAttributes(
+				attribute.Bool("storage.full", true),
+				attribute.Int("storage.evicted_items", evicted),
+			)
+		}
+	}
+
+	// Observers run once the deferred unlock below has released the lock
+	var event StorageEvent
+	defer func() { s.notify(event) }()
 
-	s.items[item.ID] = item
+	sh.lock(ctx, span, "create")
+	defer sh.mutex.Unlock()
+
+	// Re-check after waiting for the lock, the deadline may have passed meanwhile
+	if err := checkContext(ctx, span); err != nil {
+		return nil, err
+	}
+
+	// Overwriting is reserved for Upsert
+	if _, exists := sh.items[item.ID]; exists {
+		return nil, createConflict(span, item.ID)
+	}
+	if !s.reserve() {
+		span.SetAttributes(attribute.Bool("storage.full", true))
+		err := fmt.Errorf("create %s: %w", item.ID, ErrStorageFull)
+		span.RecordError(err)
+		return nil, err
+	}
+	s.bytes.Add(estimateItemBytes(item))
+	sh.items[item.ID] = item
+	if s.creations != nil {
+		s.creations.add(item)
+	}
+	event = newEvent(OperationCreate, item)
 	
-	span.SetAttributes(attribute.Int("storage.total_items", len(s.items)))
+	span.SetAttributes(attribute.Int("storage.total_items", int(s.size.Load())))
 	return item, nil
 }
 
+// createConflict records on span that a create hit a taken ID
+func createConflict(span trace.Span, id string) error {
+	span.SetAttributes(attribute.Bool("item.exists", true))
+	err := fmt.Errorf("create %s: %w", id, ErrItemExists)
+	span.RecordError(err)
+	return err
+}
+
 // GetByID retrieves an item by its ID
 func (s *MemoryStorage) GetByID(ctx context.Context, id string) (*models.Item, error) {
-	ctx, span := tracer.Start(ctx, "storage.get_item_by_id")
+	ctx, span := startSpan(ctx, "storage.get_item_by_id")
 	defer span.End()
 
-	span.SetAttributes(attribute.String("item.id", id))
+	if err := checkContext(ctx, span); err != nil {
+		return nil, err
+	}
 
-	s.mutex.RLock()
-	defer s.mutex.RUnlock()
+	span.SetAttributes(attribute.String("item.id", id))
 
-	item, exists := s.items[id]
-	if !exists {
+	var item *models.Item
+	var err error
+	if s.cache == nil {
+		item, err = s.getByID(ctx, span, id, false)
+	} else {
+		var result string
+		item, result, err = s.cache.get(ctx, id, func() (*models.Item, error) {
+			return s.getByID(ctx, span, id, true)
+		})
+		recordCacheLookup(ctx, result)
+		span.SetAttributes(
+			attribute.Bool("cache.hit", result != cacheMiss),
+			attribute.String("cache.result", result),
+		)
+	}
+	if IsNotFound(err) {
 		span.SetAttributes(attribute.Bool("item.found", false))
-		span.RecordError(ErrItemNotFound)
-		return nil, ErrItemNotFound
+		span.RecordError(err)
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	span.SetAttributes(
-		attribute.Bool("item.found", true),
-		attribute.String("item.name", item.Name),
-	)
+	span.SetAttributes(attribute.Bool("item.found", true))
+	telemetry.SetDetail(span, attribute.String("item.name", item.Name))
 	return item, nil
 }
 
-// GetAll retrieves all items
+// getByID reads the item from its shard, returning a copy when it is going
+// into the read cache
+func (s *MemoryStorage) getByID(ctx context.Context, span trace.Span, id string, copyItem bool) (*models.Item, error) {
+	sh := s.shardFor(id)
+	sh.rlock(ctx, span, "get_by_id")
+	defer sh.mutex.RUnlock()
+
+	if err := checkContext(ctx, span); err != nil {
+		return nil, err
+	}
+
+	item, exists := sh.items[id]
+	if !exists {
+		return nil, fmt.Errorf("get %s: %w", id, ErrItemNotFound)
+	}
+	if copyItem {
+		snapshot := *item
+		return &snapshot, nil
+	}
+	return item, nil
+}
+
+// GetAll retrieves all items, oldest first
 func (s *MemoryStorage) GetAll(ctx context.Context) ([]*models.Item, error) {
-	ctx, span := tracer.Start(ctx, "storage.get_all_items")
+	ctx, span := startSpan(ctx, "storage.get_all_items")
 	defer span.End()
 
-	s.mutex.RLock()
-	defer s.mutex.RUnlock()
+	if err := checkContext(ctx, span); err != nil {
+		return nil, err
+	}
+
+	items := make([]*models.Item, 0, s.size.Load())
+	for _, sh := range s.shards {
+		sh.rlock(ctx, span, "get_all")
+		if err := checkContext(ctx, span); err != nil {
+			sh.mutex.RUnlock()
+			return nil, err
+		}
+		for _, item := range sh.items {
+			items = append(items, item)
+		}
+		sh.mutex.RUnlock()
+	}
+
+	sortByCreation(items)
+	span.SetAttributes(attribute.Int("items.count", len(items)))
+	return items, nil
+}
+
+// GetAllForOwner retrieves all items belonging to the given owner, oldest first
+func (s *MemoryStorage) GetAllForOwner(ctx context.Context, owner string) ([]*models.Item, error) {
+	ctx, span := startSpan(ctx, "storage.get_all_items_for_owner")
+	defer span.End()
+
+	if err := checkContext(ctx, span); err != nil {
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.String("tenant.id", owner))
+
+	items := make([]*models.Item, 0)
+	for _, sh := range s.shards {
+		sh.rlock(ctx, span, "get_all_for_owner")
+		if err := checkContext(ctx, span); err != nil {
+			sh.mutex.RUnlock()
+			return nil, err
+		}
+		for _, item := range sh.items {
+			if item.Owner == owner {
+				items = append(items, item)
+			}
+		}
+		sh.mutex.RUnlock()
+	}
+
+	sortByCreation(items)
+	span.SetAttributes(attribute.Int("items.count", len(items)))
+	return items, nil
+}
+
+// FilterByTimeRange retrieves all items whose timestamps fall within the
+// range, oldest first
+func (s *MemoryStorage) FilterByTimeRange(ctx context.Context, r TimeRange) ([]*models.Item, error) {
+	ctx, span := startSpan(ctx, "storage.filter_by_time_range")
+	defer span.End()
+
+	if err := checkContext(ctx, span); err != nil {
+		return nil, err
+	}
+
+	span.SetAttributes(timeRangeAttributes(r)...)
 
-	items := make([]*models.Item, 0, len(s.items))
-	for _, item := range s.items {
-		items = append(items, item)
+	items := make([]*models.Item, 0)
+	for _, sh := range s.shards {
+		sh.rlock(ctx, span, "filter_by_time_range")
+		if err := checkContext(ctx, span); err != nil {
+			sh.mutex.RUnlock()
+			return nil, err
+		}
+		for _, item := range sh.items {
+			if r.Matches(item) {
+				items = append(items, item)
+			}
+		}
+		sh.mutex.RUnlock()
 	}
 
+	sortByCreation(items)
 	span.SetAttributes(attribute.Int("items.count", len(items)))
 	return items, nil
 }
 
+// timeRangeAttributes describes the set bounds of a range as span attributes
+func timeRangeAttributes(r TimeRange) []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, 4)
+	bounds := []struct {
+		key string
+		t   time.Time
+	}{
+		{"filter.created_after", r.CreatedAfter},
+		{"filter.created_before", r.CreatedBefore},
+		{"filter.updated_after", r.UpdatedAfter},
+		{"filter.updated_before", r.UpdatedBefore},
+	}
+	for _, b := range bounds {
+		if !b.t.IsZero() {
+			attrs = append(attrs, attribute.String(b.key, b.t.Format(time.RFC3339)))
+		}
+	}
+	return attrs
+}
+
 // Update modifies an existing item
 func (s *MemoryStorage) Update(ctx context.Context, id string, name, description string) (*models.Item, error) {
-	ctx, span := tracer.Start(ctx, "storage.update_item")
+	ctx, span := startSpan(ctx, "storage.update_item")
 	defer span.End()
 
-	span.SetAttributes(
-		attribute.String("item.id", id),
-		attribute.String("item.new_name", name),
-	)
+	if err := checkContext(ctx, span); err != nil {
+		return nil, err
+	}
 
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
+	span.SetAttributes(attribute.String("item.id", id))
+	telemetry.SetDetail(span, attribute.String("item.new_name", name))
 
-	item, exists := s.items[id]
+	var event StorageEvent
+	defer func() { s.notify(event) }()
+
+	sh := s.shardFor(id)
+	sh.lock(ctx, span, "update")
+	defer sh.mutex.Unlock()
+
+	if err := checkContext(ctx, span); err != nil {
+		return nil, err
+	}
+
+	item, exists := sh.items[id]
 	if !exists {
 		span.SetAttributes(attribute.Bool("item.found", false))
-		span.RecordError(ErrItemNotFound)
-		return nil, ErrItemNotFound
+		err := fmt.Errorf("update %s: %w", id, ErrItemNotFound)
+		span.RecordError(err)
+		return nil, err
 	}
 
-	oldName := item.Name
-	item.Update(name, description)
+	// Readers may hold the stored pointer, so the new version is a copy
+	before := *item
+	s.recordHistory(sh, item)
+	updated := before
+	updated.Update(name, description)
+	item = &updated
+	sh.items[id] = item
+	s.bytes.Add(estimateItemBytes(item) - estimateItemBytes(&before))
+	event = newEvent(OperationUpdate, item)
 	
 	span.SetAttributes(
 		attribute.Bool("item.found", true),
-		attribute.String("item.old_name", oldName),
+		attribute.String("update.changed_fields", changedFields(&before, item)),
+	)
+	telemetry.SetDetail(span,
+		attribute.String("item.old_name", before.Name),
 		attribute.String("item.updated_name", item.Name),
 	)
 	
 	return item, nil
 }
 
+// Upsert stores item under id, replacing the name, description and metadata
+// of an existing item or creating it otherwise. It reports whether it was created.
+func (s *MemoryStorage) Upsert(ctx context.Context, id string, item *models.Item) (*models.Item, bool, error) {
+	ctx, span := startSpan(ctx, "storage.upsert_item")
+	defer span.End()
+
+	if err := checkContext(ctx, span); err != nil {
+		return nil, false, err
+	}
+
+	span.SetAttributes(attribute.String("item.id", id))
+	telemetry.SetDetail(span, attribute.String("item.new_name", item.Name))
+
+	sh := s.shardFor(id)
+
+	// Make room for a new item before taking the lock, eviction locks other shards
+	if s.maxItems > 0 {
+		sh.rlock(ctx, span, "upsert")
+		_, exists := sh.items[id]
+		sh.mutex.RUnlock()
+		if !exists {
+			if evicted := s.makeRoom(ctx, span); evicted > 0 {
+				span.SetAttributes(attribute.Int("storage.evicted_items", evicted))
+			}
+		}
+	}
+
+	var event StorageEvent
+	defer func() { s.notify(event) }()
+
+	sh.lock(ctx, span, "upsert")
+	defer sh.mutex.Unlock()
+
+	if err := checkContext(ctx, span); err != nil {
+		return nil, false, err
+	}
+
+	if existing, exists := sh.items[id]; exists {
+		// Readers may hold the stored pointer, so the new version is a copy
+		before := *existing
+		s.recordHistory(sh, existing)
+		replaced := before
+		replaced.Replace(item.Name, item.Description, item.Metadata)
+		sh.items[id] = &replaced
+		s.bytes.Add(estimateItemBytes(&replaced) - estimateItemBytes(&before))
+		event = newEvent(OperationUpdate, &replaced)
+
+		span.SetAttributes(
+			attribute.Bool("upsert.created", false),
+			attribute.String("update.changed_fields", changedFields(&before, &replaced)),
+		)
+		return &replaced, false, nil
+	}
+
+	if !s.reserve() {
+		span.SetAttributes(attribute.Bool("storage.full", true))
+		err := fmt.Errorf("upsert %s: %w", id, ErrStorageFull)
+		span.RecordError(err)
+		return nil, false, err
+	}
+	item.ID = id
+	sh.items[id] = item
+	if s.creations != nil {
+		s.creations.add(item)
+	}
+	s.bytes.Add(estimateItemBytes(item))
+	event = newEvent(OperationCreate, item)
+
+	span.SetAttributes(
+		attribute.Bool("upsert.created", true),
+		attribute.Int("storage.total_items", int(s.size.Load())),
+	)
+	return item, true, nil
+}
+
 // Delete removes an item by its ID
 func (s *MemoryStorage) Delete(ctx context.Context, id string) error {
-	ctx, span := tracer.Start(ctx, "storage.delete_item")
+	ctx, span := startSpan(ctx, "storage.delete_item")
 	defer span.End()
 
+	if err := checkContext(ctx, span); err != nil {
+		return err
+	}
+
 	span.SetAttributes(attribute.String("item.id", id))
 
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
+	var event StorageEvent
+	defer func() { s.notify(event) }()
+
+	sh := s.shardFor(id)
+	sh.lock(ctx, span, "delete")
+	defer sh.mutex.Unlock()
+
+	if err := checkContext(ctx, span); err != nil {
+		return err
+	}
 
-	item, exists := s.items[id]
+	item, exists := sh.items[id]
 	if !exists {
 		span.SetAttributes(attribute.Bool("item.found", false))
-		span.RecordError(ErrItemNotFound)
-		return ErrItemNotFound
+		err := fmt.Errorf("delete %s: %w", id, ErrItemNotFound)
+		span.RecordError(err)
+		return err
 	}
 
-	delete(s.items, id)
+	delete(sh.items, id)
+	if s.creations != nil {
+		s.creations.remove(id)
+	}
+	freed := estimateItemBytes(item)
+	for i := range sh.history[id] {
+		freed += estimateItemBytes(&sh.history[id][i])
+	}
+	s.bytes.Add(-freed)
+	delete(sh.history, id)
+	event = newEvent(OperationDelete, item)
+	remaining := s.size.Add(-1)
 	
 	span.SetAttributes(
 		attribute.Bool("item.found", true),
-		attribute.String("item.deleted_name", item.Name),
-		attribute.Int("storage.remaining_items", len(s.items)),
+		attribute.Int("storage.remaining_items", int(remaining)),
 	)
+	telemetry.SetDetail(span, attribute.String("item.deleted_name", item.Name))
 	
 	return nil
 }
 
 // Count returns the total number of items
 func (s *MemoryStorage) Count(ctx context.Context) (int, error) {
-	ctx, span := tracer.Start(ctx, "storage.count_items")
+	ctx, span := startSpan(ctx, "storage.count_items")
 	defer span.End()
 
-	s.mutex.RLock()
-	defer s.mutex.RUnlock()
+	if err := checkContext(ctx, span); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, sh := range s.shards {
+		sh.rlock(ctx, span, "count")
+		count += len(sh.items)
+		sh.mutex.RUnlock()
+	}
+
+	if err := checkContext(ctx, span); err != nil {
+		return 0, err
+	}
 
-	count := len(s.items)
 	span.SetAttributes(attribute.Int("items.count", count))
 	
 	return count, nil
 }
+
+// Ping reports whether the storage is reachable, which for memory is always
+func (s *MemoryStorage) Ping(ctx context.Context) error {
+	ctx, span := startSpan(ctx, "storage.ping")
+	defer span.End()
+
+	return checkContext(ctx, span)
+}
+
+// changedFields lists the user-editable fields that differ between two
+// versions of an item, comma-separated, or "none" for a no-op update
+func changedFields(before, after *models.Item) string {
+	var fields []string
+	if before.Name != after.Name {
+		fields = append(fields, "name")
+	}
+	if before.Description != after.Description {
+		fields = append(fields, "description")
+	}
+	if !models.MetadataEqual(before.Metadata, after.Metadata) {
+		fields = append(fields, "metadata")
+	}
+	if before.Flagged != after.Flagged {
+		fields = append(fields, "flagged")
+	}
+	if len(fields) == 0 {
+		return "none"
+	}
+	return strings.Join(fields, ",")
+}
+
+// recordHistory keeps a copy of the item's current version before it is
+// modified, dropping the oldest versions beyond the limit. Callers must hold
+// the shard's write lock.
+func (s *MemoryStorage) recordHistory(sh *shard, item *models.Item) {
+	if s.historyLimit <= 0 {
+		return
+	}
+	versions := append(sh.history[item.ID], *item)
+	s.bytes.Add(estimateItemBytes(item))
+	if len(versions) > s.historyLimit {
+		for i := range versions[:len(versions)-s.historyLimit] {
+			s.bytes.Add(-estimateItemBytes(&versions[i]))
+		}
+		versions = versions[len(versions)-s.historyLimit:]
+	}
+	sh.history[item.ID] = versions
+}
+
+// History returns the item's past versions, oldest first
+func (s *MemoryStorage) History(ctx context.Context, id string) ([]models.Item, error) {
+	ctx, span := startSpan(ctx, "storage.get_item_history")
+	defer span.End()
+
+	if err := checkContext(ctx, span); err != nil {
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.String("item.id", id))
+
+	sh := s.shardFor(id)
+	sh.rlock(ctx, span, "history")
+	defer sh.mutex.RUnlock()
+
+	if err := checkContext(ctx, span); err != nil {
+		return nil, err
+	}
+
+	if _, exists := sh.items[id]; !exists {
+		span.SetAttributes(attribute.Bool("item.found", false))
+		err := fmt.Errorf("history %s: %w", id, ErrItemNotFound)
+		span.RecordError(err)
+		return nil, err
+	}
+
+	versions := make([]models.Item, len(sh.history[id]))
+	copy(versions, sh.history[id])
+
+	span.SetAttributes(
+		attribute.Bool("item.found", true),
+		attribute.Int("history.versions", len(versions)),
+	)
+	return versions, nil
+}
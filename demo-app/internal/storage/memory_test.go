@@ -0,0 +1,179 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/misua/eks-with-otel/demo-app/internal/models"
+)
+
+func TestMemoryStorageCancelledContext(t *testing.T) {
+	tests := []struct {
+		name string
+		call func(ctx context.Context, s *MemoryStorage, id string) error
+	}{
+		{"Create", func(ctx context.Context, s *MemoryStorage, _ string) error {
+			_, err := s.Create(ctx, models.NewItem("new", "item"))
+			return err
+		}},
+		{"GetByID", func(ctx context.Context, s *MemoryStorage, id string) error {
+			_, err := s.GetByID(ctx, id)
+			return err
+		}},
+		{"GetAll", func(ctx context.Context, s *MemoryStorage, _ string) error {
+			_, err := s.GetAll(ctx)
+			return err
+		}},
+		{"GetAllForOwner", func(ctx context.Context, s *MemoryStorage, _ string) error {
+			_, err := s.GetAllForOwner(ctx, "owner")
+			return err
+		}},
+		{"Update", func(ctx context.Context, s *MemoryStorage, id string) error {
+			_, err := s.Update(ctx, id, "renamed", "")
+			return err
+		}},
+		{"Upsert", func(ctx context.Context, s *MemoryStorage, id string) error {
+			_, _, err := s.Upsert(ctx, id, models.NewItemWithID(id, "replaced", ""))
+			return err
+		}},
+		{"Delete", func(ctx context.Context, s *MemoryStorage, id string) error {
+			return s.Delete(ctx, id)
+		}},
+		{"Count", func(ctx context.Context, s *MemoryStorage, _ string) error {
+			_, err := s.Count(ctx)
+			return err
+		}},
+	}
+
+	contexts := []struct {
+		name    string
+		ctx     func() context.Context
+		wantErr error
+	}{
+		{"cancelled", func() context.Context {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			return ctx
+		}, context.Canceled},
+		{"deadline exceeded", func() context.Context {
+			ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+			t.Cleanup(cancel)
+			return ctx
+		}, context.DeadlineExceeded},
+	}
+
+	for _, tt := range tests {
+		for _, cc := range contexts {
+			t.Run(tt.name+"/"+cc.name, func(t *testing.T) {
+				s := NewMemoryStorage()
+				item, err := s.Create(context.Background(), models.NewItem("stored", "item"))
+				if err != nil {
+					t.Fatalf("create: %v", err)
+				}
+
+				if err := tt.call(cc.ctx(), s, item.ID); !errors.Is(err, cc.wantErr) {
+					t.Fatalf("error = %v, want %v", err, cc.wantErr)
+				}
+
+				// Nothing may have changed
+				stored, err := s.GetByID(context.Background(), item.ID)
+				if err != nil {
+					t.Fatalf("stored item gone: %v", err)
+				}
+				if stored.Name != "stored" || stored.Version != 1 {
+					t.Errorf("stored item changed: %+v", stored)
+				}
+				if count, _ := s.Count(context.Background()); count != 1 {
+					t.Errorf("count = %d, want 1", count)
+				}
+			})
+		}
+	}
+}
+
+func TestCreateDuplicateDoesNotEvict(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxItems int
+		existing []string
+		// duplicate is the taken ID the create is retried with
+		duplicate string
+	}{
+		{"only item", 1, []string{"a"}, "a"},
+		{"newest of a full store", 2, []string{"a", "b"}, "b"},
+		{"oldest of a full store", 2, []string{"a", "b"}, "a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s := NewMemoryStorageWithOptions(Options{MaxItems: tt.maxItems, FullPolicy: FullPolicyEvict})
+			base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+			for i, id := range tt.existing {
+				item := models.NewItemWithID(id, "item "+id, "")
+				item.CreatedAt = base.Add(time.Duration(i) * time.Minute)
+				if _, err := s.Create(ctx, item); err != nil {
+					t.Fatalf("create %s: %v", id, err)
+				}
+			}
+
+			_, err := s.Create(ctx, models.NewItemWithID(tt.duplicate, "duplicate", ""))
+			if !errors.Is(err, ErrItemExists) {
+				t.Fatalf("duplicate create: err %v, want ErrItemExists", err)
+			}
+			for _, id := range tt.existing {
+				item, err := s.GetByID(ctx, id)
+				if err != nil {
+					t.Fatalf("item %s evicted by the rejected create: %v", id, err)
+				}
+				if item.Name != "item "+id {
+					t.Errorf("item %s name %q, want it untouched", id, item.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestEvictionFollowsCreationOrder(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStorageWithOptions(Options{Shards: 4, MaxItems: 4, FullPolicy: FullPolicyEvict})
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	add := func(id string, minute int, upsert bool) {
+		t.Helper()
+		item := models.NewItemWithID(id, "item "+id, "")
+		item.CreatedAt = base.Add(time.Duration(minute) * time.Minute)
+		var err error
+		if upsert {
+			_, _, err = s.Upsert(ctx, id, item)
+		} else {
+			_, err = s.Create(ctx, item)
+		}
+		if err != nil {
+			t.Fatalf("store %s: %v", id, err)
+		}
+	}
+	// Stored out of creation order, b and c share a timestamp
+	add("d", 4, false)
+	add("c", 2, true)
+	add("a", 1, false)
+	add("b", 2, false)
+	if err := s.Delete(ctx, "a"); err != nil {
+		t.Fatalf("delete a: %v", err)
+	}
+	add("e", 5, false)
+
+	// The deleted a is skipped, then the tie between b and c goes by ID
+	for _, tt := range []struct {
+		id      string
+		evicted string
+	}{{"f", "b"}, {"g", "c"}, {"h", "d"}} {
+		add(tt.id, 10, false)
+		if _, err := s.GetByID(ctx, tt.evicted); !errors.Is(err, ErrItemNotFound) {
+			t.Errorf("creating %s: %s still stored (err %v), want it evicted", tt.id, tt.evicted, err)
+		}
+	}
+	if n, _ := s.Count(ctx); n != 4 {
+		t.Errorf("count %d, want 4", n)
+	}
+}
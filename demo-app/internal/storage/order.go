@@ -0,0 +1,20 @@
+package storage
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/misua/eks-with-otel/demo-app/internal/models"
+)
+
+// sortByCreation puts items in the order listings guarantee: oldest first,
+// by ID among items created at the same instant. Shard maps iterate in random
+// order, so without it the same data could be listed differently every call.
+func sortByCreation(items []*models.Item) {
+	slices.SortFunc(items, func(a, b *models.Item) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ID, b.ID)
+	})
+}
@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/misua/eks-with-otel/demo-app/internal/models"
+)
+
+func TestListingOrderIsStable(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStorage()
+
+	// Three creation instants with several items each, created newest
+	// first and spread over the shards
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var want []string
+	created := map[string]time.Time{}
+	for minute := range 3 {
+		for _, suffix := range []string{"a", "b", "c", "d", "e"} {
+			id := fmt.Sprintf("%d-%s", minute, suffix)
+			want = append(want, id)
+			created[id] = base.Add(time.Duration(minute) * time.Minute)
+		}
+	}
+	for i := len(want) - 1; i >= 0; i-- {
+		item := models.NewItemWithID(want[i], "item "+want[i], "")
+		item.Owner = "tenant"
+		item.CreatedAt = created[want[i]]
+		item.UpdatedAt = item.CreatedAt
+		if _, err := s.Create(ctx, item); err != nil {
+			t.Fatalf("create %s: %v", want[i], err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		list func() ([]*models.Item, error)
+	}{
+		{"GetAll", func() ([]*models.Item, error) { return s.GetAll(ctx) }},
+		{"GetAllForOwner", func() ([]*models.Item, error) { return s.GetAllForOwner(ctx, "tenant") }},
+		{"FilterByTimeRange", func() ([]*models.Item, error) {
+			return s.FilterByTimeRange(ctx, TimeRange{CreatedAfter: base.Add(-time.Hour)})
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Map iteration order changes between calls, so repeat enough
+			// times for an unsorted listing to show
+			for call := 0; call < 20; call++ {
+				items, err := tt.list()
+				if err != nil {
+					t.Fatalf("list: %v", err)
+				}
+				if len(items) != len(want) {
+					t.Fatalf("got %d items, want %d", len(items), len(want))
+				}
+				for i, item := range items {
+					if item.ID != want[i] {
+						t.Fatalf("call %d: position %d is %s, want %s", call, i, item.ID, want[i])
+					}
+				}
+			}
+		})
+	}
+}
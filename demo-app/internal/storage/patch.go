@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/misua/eks-with-otel/demo-app/internal/models"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// CheckVersion fails with ErrVersionMismatch when expected is set and the
+// item is at another version. Conditional writes call it under the shard's
+// write lock, e.g. from a PatchItem edit.
+func CheckVersion(item *models.Item, expected int) error {
+	if expected > 0 && item.Version != expected {
+		return fmt.Errorf("item %s is at version %d, not %d: %w", item.ID, item.Version, expected, ErrVersionMismatch)
+	}
+	return nil
+}
+
+// PatchItem applies edit to a copy of the item under the shard's write lock
+// and stores the copy in its place, so readers holding the old version never
+// see it change and the read-modify-write of a partial update cannot
+// interleave with other writes to the item. An error from edit leaves the
+// item untouched and is returned as is; edit is expected to bump the version.
+func (s *MemoryStorage) PatchItem(ctx context.Context, id string, edit func(item *models.Item) error) (*models.Item, error) {
+	ctx, span := startSpan(ctx, "storage.patch_item")
+	defer span.End()
+
+	if err := checkContext(ctx, span); err != nil {
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.String("item.id", id))
+
+	var event StorageEvent
+	defer func() { s.notify(event) }()
+
+	sh := s.shardFor(id)
+	sh.lock(ctx, span, "patch_item")
+	defer sh.mutex.Unlock()
+
+	if err := checkContext(ctx, span); err != nil {
+		return nil, err
+	}
+
+	item, exists := sh.items[id]
+	if !exists {
+		span.SetAttributes(attribute.Bool("item.found", false))
+		err := fmt.Errorf("patch %s: %w", id, ErrItemNotFound)
+		span.RecordError(err)
+		return nil, err
+	}
+
+	patched := *item
+	if err := edit(&patched); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	s.recordHistory(sh, item)
+	s.bytes.Add(estimateItemBytes(&patched) - estimateItemBytes(item))
+	span.SetAttributes(
+		attribute.Bool("item.found", true),
+		attribute.String("update.changed_fields", changedFields(item, &patched)),
+	)
+	sh.items[id] = &patched
+	event = newEvent(OperationUpdate, &patched)
+
+	snapshot := patched
+	return &snapshot, nil
+}
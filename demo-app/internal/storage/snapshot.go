@@ -0,0 +1,242 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/misua/eks-with-otel/demo-app/internal/models"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// snapshotVersion is bumped whenever the snapshot format changes incompatibly
+const snapshotVersion = 1
+
+var snapshotDuration, _ = meter.Float64Histogram(
+	"storage.snapshot.duration",
+	metric.WithDescription("Time taken to write a storage snapshot"),
+	metric.WithUnit("ms"),
+)
+
+// snapshot is the JSON document written by SaveSnapshot. Version history is
+// not included, restored items start without past versions.
+type snapshot struct {
+	Version int            `json:"version"`
+	SavedAt time.Time      `json:"saved_at"`
+	Items   []*models.Item `json:"items"`
+}
+
+// SaveSnapshot writes every item to path as JSON and returns how many were
+// written. All shards are read-locked together while the items are copied so
+// the file is consistent; marshalling and the disk write happen after the
+// locks are released so writers only wait for the copy. The file is replaced
+// atomically so a crash mid-write keeps the previous one.
+func (s *MemoryStorage) SaveSnapshot(ctx context.Context, path string) (saved int, err error) {
+	ctx, span := startSpan(ctx, "storage.save_snapshot")
+	defer span.End()
+
+	start := time.Now()
+	defer func() {
+		result := "success"
+		if err != nil {
+			result = "error"
+		}
+		snapshotDuration.Record(ctx, float64(time.Since(start))/float64(time.Millisecond),
+			metric.WithAttributes(attribute.String("result", result)))
+	}()
+
+	span.SetAttributes(attribute.String("snapshot.path", path))
+
+	if err := checkContext(ctx, span); err != nil {
+		return 0, err
+	}
+
+	snap := snapshot{Version: snapshotVersion, SavedAt: time.Now().UTC()}
+	for _, sh := range s.shards {
+		sh.rlock(ctx, span, "save_snapshot")
+	}
+	snap.Items = make([]*models.Item, 0, s.size.Load())
+	for _, sh := range s.shards {
+		for _, item := range sh.items {
+			saved := *item
+			snap.Items = append(snap.Items, &saved)
+		}
+	}
+	for _, sh := range s.shards {
+		sh.mutex.RUnlock()
+	}
+
+	data, err := json.Marshal(snap)
+	if err != nil {
+		span.RecordError(err)
+		return 0, err
+	}
+	if err := writeFileAtomic(path, data); err != nil {
+		span.RecordError(err)
+		return 0, err
+	}
+
+	span.SetAttributes(
+		attribute.Int("snapshot.items", len(snap.Items)),
+		attribute.Int("snapshot.bytes", len(data)),
+	)
+	return len(snap.Items), nil
+}
+
+// LoadSnapshot replaces the whole store with the items saved at path and
+// returns how many were restored. The store is left untouched when the file
+// cannot be read or does not fit MaxItems. Observers are not notified. The
+// sequential ID strategy is moved past the highest numeric ID restored.
+func (s *MemoryStorage) LoadSnapshot(ctx context.Context, path string) (int, error) {
+	ctx, span := startSpan(ctx, "storage.load_snapshot")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("snapshot.path", path))
+
+	if err := checkContext(ctx, span); err != nil {
+		return 0, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		span.RecordError(err)
+		return 0, err
+	}
+	var snap snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		span.RecordError(err)
+		return 0, fmt.Errorf("%w: %v", ErrInvalidSnapshot, err)
+	}
+	if snap.Version != snapshotVersion {
+		err := fmt.Errorf("%w: unsupported version %d", ErrInvalidSnapshot, snap.Version)
+		span.RecordError(err)
+		return 0, err
+	}
+	if s.maxItems > 0 && len(snap.Items) > s.maxItems {
+		err := fmt.Errorf("restore %d items over a limit of %d: %w", len(snap.Items), s.maxItems, ErrStorageFull)
+		span.RecordError(err)
+		return 0, err
+	}
+
+	for _, sh := range s.shards {
+		sh.lock(ctx, span, "load_snapshot")
+		defer sh.mutex.Unlock()
+	}
+
+	if err := checkContext(ctx, span); err != nil {
+		return 0, err
+	}
+
+	for _, sh := range s.shards {
+		clear(sh.items)
+		clear(sh.history)
+	}
+	var highestID uint64
+	for _, item := range snap.Items {
+		if item == nil || item.ID == "" {
+			continue
+		}
+		s.shardFor(item.ID).items[item.ID] = item
+		if n, err := strconv.ParseUint(item.ID, 10, 64); err == nil {
+			highestID = max(highestID, n)
+		}
+	}
+	models.AdvanceSequence(highestID)
+	restored, bytes := 0, int64(0)
+	var all []*models.Item
+	for _, sh := range s.shards {
+		restored += len(sh.items)
+		for _, item := range sh.items {
+			bytes += estimateItemBytes(item)
+			if s.creations != nil {
+				all = append(all, item)
+			}
+		}
+	}
+	if s.creations != nil {
+		s.creations.reset(all)
+	}
+	s.size.Store(int64(restored))
+	s.bytes.Store(bytes)
+	if s.cache != nil {
+		s.cache.clear()
+	}
+
+	span.SetAttributes(
+		attribute.Int("snapshot.items", restored),
+		attribute.String("snapshot.saved_at", snap.SavedAt.Format(time.RFC3339)),
+	)
+	return restored, nil
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames it
+// over path, so readers only ever see a complete file
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// StartSnapshots saves a snapshot to path every interval in the background
+// until Close, which writes a final one so a graceful shutdown loses nothing.
+// Failures are recorded on the snapshot spans and the duration metric.
+func (s *MemoryStorage) StartSnapshots(path string, interval time.Duration) {
+	s.snapshotStop = make(chan struct{})
+	s.snapshotDone = make(chan struct{})
+
+	go func() {
+		defer close(s.snapshotDone)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				s.SaveSnapshot(context.Background(), path)
+			case <-s.snapshotStop:
+				s.SaveSnapshot(context.Background(), path)
+				return
+			}
+		}
+	}()
+}
+
+// Close stops background snapshots once the final one is written and
+// unregisters the store's gauge callbacks. It is safe to call more than once
+// and without StartSnapshots.
+func (s *MemoryStorage) Close() error {
+	s.closeOnce.Do(func() {
+		if s.snapshotStop != nil {
+			close(s.snapshotStop)
+			<-s.snapshotDone
+		}
+		for _, registration := range s.metricCallbacks {
+			registration.Unregister()
+		}
+		s.metricCallbacks = nil
+	})
+	return nil
+}
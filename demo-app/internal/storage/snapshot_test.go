@@ -0,0 +1,120 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/misua/eks-with-otel/demo-app/internal/models"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "items.json")
+
+	saved := NewMemoryStorage()
+	want := map[string]string{}
+	for _, name := range []string{"first", "second", "third"} {
+		item, err := saved.Create(ctx, models.NewItem(name, "saved"))
+		if err != nil {
+			t.Fatalf("create %s: %v", name, err)
+		}
+		want[item.ID] = name
+	}
+	if n, err := saved.SaveSnapshot(ctx, path); err != nil || n != len(want) {
+		t.Fatalf("SaveSnapshot = %d, %v; want %d items", n, err, len(want))
+	}
+
+	// The locks are released once the items are copied: writes go on
+	// during and after the save
+	if _, err := saved.Create(ctx, models.NewItem("after", "not saved")); err != nil {
+		t.Fatalf("create after save: %v", err)
+	}
+
+	restored := NewMemoryStorage()
+	if n, err := restored.LoadSnapshot(ctx, path); err != nil || n != len(want) {
+		t.Fatalf("LoadSnapshot = %d, %v; want %d items", n, err, len(want))
+	}
+	for id, name := range want {
+		item, err := restored.GetByID(ctx, id)
+		if err != nil {
+			t.Fatalf("restored item %s: %v", id, err)
+		}
+		if item.Name != name {
+			t.Errorf("restored item %s name %q, want %q", id, item.Name, name)
+		}
+	}
+}
+
+func TestLoadSnapshotAdvancesSequentialIDs(t *testing.T) {
+	models.SetIDStrategy(models.IDStrategySequential)
+	t.Cleanup(func() { models.SetIDStrategy(models.IDStrategyUUID) })
+
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "items.json")
+
+	saved := NewMemoryStorage()
+	for _, id := range []string{"3", "41", "7", "not-a-number"} {
+		if _, err := saved.Create(ctx, models.NewItemWithID(id, "item "+id, "")); err != nil {
+			t.Fatalf("create %s: %v", id, err)
+		}
+	}
+	if _, err := saved.SaveSnapshot(ctx, path); err != nil {
+		t.Fatalf("SaveSnapshot: %v", err)
+	}
+
+	// A restart counts from 1 again until the restore moves it on
+	models.SetIDStrategy(models.IDStrategySequential)
+	restored := NewMemoryStorage()
+	if _, err := restored.LoadSnapshot(ctx, path); err != nil {
+		t.Fatalf("LoadSnapshot: %v", err)
+	}
+
+	item, err := restored.Create(ctx, models.NewItem("new", "after restore"))
+	if err != nil {
+		t.Fatalf("create after restore: %v", err)
+	}
+	if item.ID != "42" {
+		t.Errorf("first ID after restore = %s, want 42", item.ID)
+	}
+}
+
+func TestLoadSnapshotKeepsEvictionOrder(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "items.json")
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	saved := NewMemoryStorage()
+	for i, id := range []string{"b", "a", "c"} {
+		item := models.NewItemWithID(id, "item "+id, "")
+		item.CreatedAt = base.Add(time.Duration(i) * time.Minute)
+		if _, err := saved.Create(ctx, item); err != nil {
+			t.Fatalf("create %s: %v", id, err)
+		}
+	}
+	if _, err := saved.SaveSnapshot(ctx, path); err != nil {
+		t.Fatalf("SaveSnapshot: %v", err)
+	}
+
+	// Items held before the restore are replaced and must not be evicted instead
+	restored := NewMemoryStorageWithOptions(Options{MaxItems: 3, FullPolicy: FullPolicyEvict})
+	if _, err := restored.Create(ctx, models.NewItemWithID("old", "replaced by the restore", "")); err != nil {
+		t.Fatalf("create old: %v", err)
+	}
+	if _, err := restored.LoadSnapshot(ctx, path); err != nil {
+		t.Fatalf("LoadSnapshot: %v", err)
+	}
+	if _, err := restored.Create(ctx, models.NewItemWithID("d", "new", "")); err != nil {
+		t.Fatalf("create d: %v", err)
+	}
+	if _, err := restored.GetByID(ctx, "b"); !errors.Is(err, ErrItemNotFound) {
+		t.Errorf("oldest restored item b still stored (err %v), want it evicted", err)
+	}
+	for _, id := range []string{"a", "c", "d"} {
+		if _, err := restored.GetByID(ctx, id); err != nil {
+			t.Errorf("item %s: %v", id, err)
+		}
+	}
+}
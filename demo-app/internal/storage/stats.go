@@ -0,0 +1,107 @@
+package storage
+
+import (
+	"context"
+	"time"
+	"unsafe"
+
+	"github.com/misua/eks-with-otel/demo-app/internal/models"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Stats is a point-in-time snapshot of the storage internals
+type Stats struct {
+	TotalItems      int     `json:"total_items"`
+	ShardItems      []int   `json:"shard_items"`
+	HistoryVersions int     `json:"history_versions"`
+	Evictions       int64   `json:"evictions"`
+	LockWaits       int64   `json:"lock_waits"`
+	LockWaitAvgMs   float64 `json:"lock_wait_avg_ms"`
+	EstimatedBytes  int64   `json:"estimated_bytes"`
+}
+
+// DebugStats walks every shard, so it is meant for debugging rather than the
+// request path
+func (s *MemoryStorage) DebugStats(ctx context.Context) (Stats, error) {
+	ctx, span := startSpan(ctx, "storage.debug_stats")
+	defer span.End()
+
+	if err := checkContext(ctx, span); err != nil {
+		return Stats{}, err
+	}
+
+	stats := Stats{
+		ShardItems: make([]int, len(s.shards)),
+		Evictions:  s.evictions.Load(),
+	}
+	var waitNanos int64
+	for i, sh := range s.shards {
+		sh.rlock(ctx, span, "debug_stats")
+		stats.ShardItems[i] = len(sh.items)
+		stats.TotalItems += len(sh.items)
+		for _, item := range sh.items {
+			stats.EstimatedBytes += estimateItemBytes(item)
+		}
+		for _, versions := range sh.history {
+			stats.HistoryVersions += len(versions)
+			for j := range versions {
+				stats.EstimatedBytes += estimateItemBytes(&versions[j])
+			}
+		}
+		sh.mutex.RUnlock()
+
+		stats.LockWaits += sh.lockWaits.Load()
+		waitNanos += sh.lockWaitNanos.Load()
+	}
+	if stats.LockWaits > 0 {
+		stats.LockWaitAvgMs = float64(waitNanos) / float64(stats.LockWaits) / float64(time.Millisecond)
+	}
+
+	span.SetAttributes(
+		attribute.Int("storage.total_items", stats.TotalItems),
+		attribute.Int("storage.shards", len(s.shards)),
+		attribute.Int64("storage.estimated_bytes", stats.EstimatedBytes),
+	)
+	return stats, nil
+}
+
+var memoryGauge, _ = meter.Int64ObservableGauge(
+	"storage.memory",
+	metric.WithDescription("Estimated memory held by stored items and their history"),
+	metric.WithUnit("By"),
+)
+
+// registerMemoryGauge exports the footprint estimate, which is kept up to
+// date on every mutation so collection never has to walk the shards
+func (s *MemoryStorage) registerMemoryGauge() {
+	s.registerCallback(func(_ context.Context, o metric.Observer) error {
+		o.ObserveInt64(memoryGauge, s.bytes.Load())
+		return nil
+	}, memoryGauge)
+}
+
+// registerCallback registers a metric callback observing this store. Close
+// unregisters it, so closed stores neither report nor stay reachable.
+func (s *MemoryStorage) registerCallback(callback metric.Callback, instruments ...metric.Observable) {
+	registration, err := meter.RegisterCallback(callback, instruments...)
+	if err != nil {
+		return
+	}
+	s.metricCallbacks = append(s.metricCallbacks, registration)
+}
+
+// estimateItemBytes approximates the heap held by an item: the struct itself
+// plus its string contents, metadata keys and string metadata values. Map and
+// slice overhead is ignored.
+func estimateItemBytes(item *models.Item) int64 {
+	n := int64(unsafe.Sizeof(*item)) +
+		int64(len(item.ID)+len(item.Name)+len(item.Description)+len(item.Owner))
+	for key, value := range item.Metadata {
+		n += int64(len(key))
+		if s, ok := value.(string); ok {
+			n += int64(len(s))
+		}
+	}
+	return n
+}
@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/misua/eks-with-otel/demo-app/internal/models"
+)
+
+// Storage is the item store used by the HTTP handlers. MemoryStorage is the
+// default implementation; other backends only need to satisfy this interface.
+//
+// GetAll, GetAllForOwner and FilterByTimeRange return items oldest first, by
+// ID among items with the same CreatedAt, so listings and their pages are
+// stable across calls.
+type Storage interface {
+	Create(ctx context.Context, item *models.Item) (*models.Item, error)
+	GetByID(ctx context.Context, id string) (*models.Item, error)
+	GetAll(ctx context.Context) ([]*models.Item, error)
+	GetAllForOwner(ctx context.Context, owner string) ([]*models.Item, error)
+	FilterByTimeRange(ctx context.Context, r TimeRange) ([]*models.Item, error)
+	Update(ctx context.Context, id string, name, description string) (*models.Item, error)
+	Upsert(ctx context.Context, id string, item *models.Item) (*models.Item, bool, error)
+	PatchBatch(ctx context.Context, ids []string, patch models.ItemPatch) ([]BatchResult, error)
+	// PatchItem applies edit to a copy of the item and stores the result
+	// atomically; an error from edit is returned unchanged
+	PatchItem(ctx context.Context, id string, edit func(item *models.Item) error) (*models.Item, error)
+	Delete(ctx context.Context, id string) error
+	// SetFlag marks or unmarks an item; setting the flag it already has is a no-op
+	SetFlag(ctx context.Context, id string, flagged bool) (*models.Item, error)
+	// Touch sets UpdatedAt to now and bumps the version, leaving the fields as they are
+	Touch(ctx context.Context, id string) (*models.Item, error)
+	Count(ctx context.Context) (int, error)
+	History(ctx context.Context, id string) ([]models.Item, error)
+
+	// GroupBy counts items per value of field, restricted to owner unless empty
+	GroupBy(ctx context.Context, field, owner string) (map[string]int, error)
+	// GetLargest returns the limit items with the longest descriptions,
+	// restricted to owner unless empty
+	GetLargest(ctx context.Context, limit int, owner string) ([]*models.Item, error)
+
+	// Ping checks that the backend is reachable
+	Ping(ctx context.Context) error
+
+	// DebugStats reports internal counters for diagnostics. Backends return
+	// zero values for whatever they do not track.
+	DebugStats(ctx context.Context) (Stats, error)
+
+	// AddObserver registers an observer for successful mutations
+	AddObserver(observer Observer)
+}
+
+var _ Storage = (*MemoryStorage)(nil)
@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// timedSpan is a storage span that splits its duration into time spent
+// waiting for shard locks and time spent doing the work, recorded as
+// storage.lock_wait_ms and storage.work_ms when it ends. Comparing the two
+// across shard counts shows what sharding buys.
+type timedSpan struct {
+	trace.Span
+	start    time.Time
+	lockWait atomic.Int64
+}
+
+// startSpan starts a storage span whose lock waits are tallied by lock and
+// rlock
+func startSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	ctx, span := tracer.Start(ctx, name)
+	return ctx, &timedSpan{Span: span, start: time.Now()}
+}
+
+// addLockWait tallies a lock wait against span if it is a timed storage span
+func addLockWait(span trace.Span, wait time.Duration) {
+	if t, ok := span.(*timedSpan); ok {
+		t.lockWait.Add(int64(wait))
+	}
+}
+
+// End records the lock wait and work split before ending the span
+func (t *timedSpan) End(options ...trace.SpanEndOption) {
+	total := time.Since(t.start)
+	wait := time.Duration(t.lockWait.Load())
+	t.Span.SetAttributes(
+		attribute.Float64("storage.lock_wait_ms", durationMs(wait)),
+		attribute.Float64("storage.work_ms", durationMs(max(total-wait, 0))),
+	)
+	t.Span.End(options...)
+}
+
+func durationMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/misua/eks-with-otel/demo-app/internal/models"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Touch sets the item's UpdatedAt to now and bumps its version. It is an
+// update like any other: the previous version goes to history and observers
+// are notified.
+func (s *MemoryStorage) Touch(ctx context.Context, id string) (*models.Item, error) {
+	ctx, span := startSpan(ctx, "storage.touch")
+	defer span.End()
+
+	if err := checkContext(ctx, span); err != nil {
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.String("item.id", id))
+
+	var event StorageEvent
+	defer func() { s.notify(event) }()
+
+	sh := s.shardFor(id)
+	sh.lock(ctx, span, "touch")
+	defer sh.mutex.Unlock()
+
+	if err := checkContext(ctx, span); err != nil {
+		return nil, err
+	}
+
+	item, exists := sh.items[id]
+	if !exists {
+		span.SetAttributes(attribute.Bool("item.found", false))
+		err := fmt.Errorf("touch %s: %w", id, ErrItemNotFound)
+		span.RecordError(err)
+		return nil, err
+	}
+
+	// Readers may hold the stored pointer, so the new version is a copy
+	s.recordHistory(sh, item)
+	touched := *item
+	touched.Touch()
+	sh.items[id] = &touched
+	event = newEvent(OperationUpdate, &touched)
+
+	span.SetAttributes(
+		attribute.Bool("item.found", true),
+		attribute.Int("item.version", touched.Version),
+	)
+	snapshot := touched
+	return &snapshot, nil
+}
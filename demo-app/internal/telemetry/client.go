@@ -0,0 +1,16 @@
+package telemetry
+
+import "sync/atomic"
+
+var recordClientIP atomic.Bool
+
+// SetRecordClientIP selects whether handler spans carry the caller's IP
+// address. It is off by default since the IP is personal data in many setups.
+func SetRecordClientIP(enabled bool) {
+	recordClientIP.Store(enabled)
+}
+
+// RecordClientIP reports whether handler spans carry the caller's IP address
+func RecordClientIP() bool {
+	return recordClientIP.Load()
+}
@@ -0,0 +1,41 @@
+// Package telemetry holds tracing helpers shared by the handlers and storage
+package telemetry
+
+import (
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// DetailMinimal keeps only IDs and counts on spans
+	DetailMinimal = "minimal"
+	// DetailFull also records high-cardinality values such as item names
+	DetailFull = "full"
+)
+
+var minimalDetail atomic.Bool
+
+// SetDetailLevel selects which span attributes are recorded. Unknown levels
+// are treated as full, the default.
+func SetDetailLevel(level string) {
+	minimalDetail.Store(level == DetailMinimal)
+}
+
+// DetailLevel returns the active detail level
+func DetailLevel() string {
+	if minimalDetail.Load() {
+		return DetailMinimal
+	}
+	return DetailFull
+}
+
+// SetDetail sets high-cardinality attributes such as names and descriptions,
+// which are skipped in minimal mode to cut trace storage
+func SetDetail(span trace.Span, attrs ...attribute.KeyValue) {
+	if minimalDetail.Load() {
+		return
+	}
+	span.SetAttributes(attrs...)
+}